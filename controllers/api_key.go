@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/storage"
+)
+
+// CreateAPIKey issues a new API key for a service account, creating the account on first use.
+// The plain text key is only returned here, storage keeps its hash.
+func (c *Controller) CreateAPIKey(ctx context.Context, serviceAccount string, kind model.Kind, expiresAt *time.Time) (model.APIKey, model.APIKeySecret, error) {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	account, err := c.userStorage.GetUserByUserName(ctx, serviceAccount)
+	switch {
+	case errors.Is(err, storage.ErrRecordNotFound):
+		account, err = c.userStorage.Register(ctx, model.User{
+			UserName:       &serviceAccount,
+			Kind:           kind,
+			ServiceAccount: true,
+		})
+		if err != nil {
+			return model.APIKey{}, "", err
+		}
+	case err != nil:
+		return model.APIKey{}, "", err
+	case !account.ServiceAccount || account.Kind != kind:
+		// never hand out keys for a human account, nor silently change a service account role
+		return model.APIKey{}, "", storage.ErrDuplicateRecord
+	}
+
+	secret, err := model.NewAPIKeySecret()
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+
+	key, err := c.apiKeyStorage.AddAPIKey(ctx, model.APIKey{
+		UserID:    account.ID,
+		Prefix:    secret.Prefix(),
+		KeyHash:   secret.Hash(),
+		CreatedBy: admin.ID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return model.APIKey{}, "", err
+	}
+	key.User = account
+
+	logging.Method(ctx, c.logger, "CreateAPIKey").Info().Msgf("CreateAPIKey: %s issued for %s by user %d", key.Prefix, serviceAccount, admin.ID)
+	return key, secret, nil
+}
+
+// GetAllAPIKeys returns every API key without their secrets
+func (c *Controller) GetAllAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	if _, err := c.requireAdministrator(ctx); err != nil {
+		return nil, err
+	}
+	return c.apiKeyStorage.GetAllAPIKeys(ctx)
+}
+
+// RevokeAPIKey stops an API key from being accepted any further
+func (c *Controller) RevokeAPIKey(ctx context.Context, id int) (model.APIKey, error) {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	key, err := c.apiKeyStorage.RevokeAPIKeyByID(ctx, id, time.Now())
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	logging.Method(ctx, c.logger, "RevokeAPIKey").Info().Msgf("RevokeAPIKey: %s revoked by user %d", key.Prefix, admin.ID)
+	return key, nil
+}
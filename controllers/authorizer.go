@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+// access is what a caller may see of an employee record
+type access int
+
+const (
+	// accessDirectory shows the fields of the staff directory, name, department, position and
+	// manager
+	accessDirectory access = iota
+	// accessFull shows the whole record
+	accessFull
+)
+
+// authorizer holds the record-level policy of employee records, every employee operation asks it
+// before touching or returning a record. Administrators act on every record; staff see and edit
+// their own record and see the directory view of everyone else.
+type authorizer struct{}
+
+// caller returns the signed in user of ctx
+func (authorizer) caller(ctx context.Context) (*model.User, error) {
+	return middleware.UserFromContext(ctx)
+}
+
+// access returns what user may see of employee
+func (authorizer) access(user *model.User, employee model.Employee) access {
+	if user.Kind == model.KindAdministrator || isOwnRecord(user, employee) {
+		return accessFull
+	}
+	return accessDirectory
+}
+
+// canManage reports whether user may add and delete employees
+func (authorizer) canManage(user *model.User) error {
+	if user.Kind != model.KindAdministrator {
+		return storage.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// canSearchEmails reports whether user may find employees by email, which would confirm the masked
+// addresses of the directory view
+func (authorizer) canSearchEmails(user *model.User) bool {
+	return user.Kind == model.KindAdministrator
+}
+
+// canUpdate reports whether user may apply changes to the stored employee. Staff only change the
+// personal fields of their own record, department and position are managed by administrators.
+func (authorizer) canUpdate(user *model.User, stored, changes model.Employee) error {
+	if user.Kind == model.KindAdministrator {
+		return nil
+	}
+	if !isOwnRecord(user, stored) {
+		return storage.ErrUnauthorizedAccess
+	}
+	if changes.DepartmentID != 0 && changes.DepartmentID != stored.DepartmentID ||
+		changes.Position != "" && changes.Position != stored.Position {
+		return storage.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// canAccessDocuments reports whether user may list, download and upload the documents of employee
+func (a authorizer) canAccessDocuments(user *model.User, employee model.Employee) error {
+	if a.access(user, employee) != accessFull {
+		return storage.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// canChangePhoto reports whether user may replace the profile photo of employee, which everyone sees
+func (a authorizer) canChangePhoto(user *model.User, employee model.Employee) error {
+	if a.access(user, employee) != accessFull {
+		return storage.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// view returns the part of employee user may see
+func (a authorizer) view(user *model.User, employee model.Employee) model.Employee {
+	if a.access(user, employee) == accessFull {
+		return employee
+	}
+	return model.Employee{
+		ID:           employee.ID,
+		TenantID:     employee.TenantID,
+		FirstName:    employee.FirstName,
+		LastName:     employee.LastName,
+		DepartmentID: employee.DepartmentID,
+		Position:     employee.Position,
+		ManagerID:    employee.ManagerID,
+		PhotoKey:     employee.PhotoKey,
+	}
+}
+
+// viewAll returns the part of every employee user may see
+func (a authorizer) viewAll(user *model.User, employees []*model.Employee) []*model.Employee {
+	for i, employee := range employees {
+		visible := a.view(user, *employee)
+		employees[i] = &visible
+	}
+	return employees
+}
+
+func isOwnRecord(user *model.User, employee model.Employee) bool {
+	return employee.UserID != 0 && employee.UserID == user.ID
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/storage"
+)
+
+func Test_Authorizer(t *testing.T) {
+	var a authorizer
+	admin := &model.User{ID: 1, Kind: model.KindAdministrator}
+	staff := &model.User{ID: 2, Kind: model.KindStaff}
+	own := model.Employee{ID: 10, UserID: 2, FirstName: "Jane", Dob: time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC), DepartmentID: 3, Position: "engineer"}
+	other := model.Employee{ID: 11, UserID: 5, FirstName: "John", Email: "john@company.com", Dob: time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC), DepartmentID: 3, Position: "lead", ManagerID: 7}
+
+	require.Equal(t, other, a.view(admin, other))
+	require.Equal(t, own, a.view(staff, own))
+	directory := a.view(staff, other)
+	require.Equal(t, model.Employee{ID: 11, FirstName: "John", DepartmentID: 3, Position: "lead", ManagerID: 7}, directory)
+
+	// an employee without a user belongs to nobody
+	require.Equal(t, accessDirectory, a.access(&model.User{Kind: model.KindStaff}, model.Employee{ID: 12}))
+
+	require.NoError(t, a.canManage(admin))
+	require.ErrorIs(t, a.canManage(staff), storage.ErrUnauthorizedAccess)
+
+	require.NoError(t, a.canUpdate(admin, other, model.Employee{Position: "manager"}))
+	require.NoError(t, a.canUpdate(staff, own, model.Employee{FirstName: "Janet", Position: "engineer"}))
+	require.ErrorIs(t, a.canUpdate(staff, own, model.Employee{Position: "manager"}), storage.ErrUnauthorizedAccess)
+	require.ErrorIs(t, a.canUpdate(staff, own, model.Employee{DepartmentID: 4}), storage.ErrUnauthorizedAccess)
+	require.ErrorIs(t, a.canUpdate(staff, other, model.Employee{FirstName: "Johnny"}), storage.ErrUnauthorizedAccess)
+
+	require.NoError(t, a.canAccessDocuments(admin, other))
+	require.NoError(t, a.canAccessDocuments(staff, own))
+	require.ErrorIs(t, a.canAccessDocuments(staff, other), storage.ErrUnauthorizedAccess)
+	require.NoError(t, a.canChangePhoto(staff, own))
+	require.ErrorIs(t, a.canChangePhoto(staff, other), storage.ErrUnauthorizedAccess)
+
+	employees := a.viewAll(staff, []*model.Employee{&own, &other})
+	require.Equal(t, own.Dob, employees[0].Dob)
+	require.True(t, employees[1].Dob.IsZero())
+	require.Zero(t, employees[1].UserID)
+}
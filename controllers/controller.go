@@ -3,12 +3,17 @@ package controller
 
 import (
 	"context"
+	"crypto/rand"
+	"io"
+	"time"
 
 	"github.com/rs/zerolog"
 
 	"employee-management-system/model"
-	"employee-management-system/pkg/environment"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/config"
 	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
 	"employee-management-system/pkg/middleware"
 	"employee-management-system/storage"
 )
@@ -25,17 +30,61 @@ type Operations interface {
 	GetEmployeeByID(ctx context.Context, ID int) (model.Employee, error)
 	GetEmployeeByContext(ctx context.Context, userID int) (model.Employee, error)
 	GetAllEmployees(ctx context.Context) ([]*model.Employee, error)
+	ListEmployees(ctx context.Context, filter model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error)
 	UpdateEmployeeByID(ctx context.Context, id int, employee model.Employee) (model.Employee, error)
 	DeleteEmployeeByID(ctx context.Context, id int) error
+
+	AddDepartment(ctx context.Context, department model.Department) (model.Department, error)
+	GetDepartmentByID(ctx context.Context, id int) (model.Department, error)
+	GetAllDepartments(ctx context.Context) ([]*model.Department, error)
+	UpdateDepartmentByID(ctx context.Context, id int, department model.Department) (model.Department, error)
+	DeleteDepartmentByID(ctx context.Context, id int) error
+
+	CreateAPIKey(ctx context.Context, serviceAccount string, kind model.Kind, expiresAt *time.Time) (model.APIKey, model.APIKeySecret, error)
+	GetAllAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) (model.APIKey, error)
+
+	CreateUser(ctx context.Context, userName string, password model.Password, kind model.Kind) (model.User, error)
+	ResetPassword(ctx context.Context, userName string, password model.Password) error
+	SetUserKind(ctx context.Context, userName string, kind model.Kind) error
+	LockUser(ctx context.Context, userName string) error
+	UnlockUser(ctx context.Context, userName string) error
+
+	GetMySessions(ctx context.Context) ([]*model.Session, error)
+	RevokeSession(ctx context.Context, id int) error
+	RevokeAllSessions(ctx context.Context, userID int) (int64, error)
+
+	ExportPersonalData(ctx context.Context, employeeID int) (model.PersonalData, error)
+	ErasePersonalData(ctx context.Context, employeeID int) error
+
+	UploadDocument(ctx context.Context, employeeID int, kind model.DocumentKind, fileName string, content io.Reader, size int64) (model.Document, error)
+	GetDocuments(ctx context.Context, employeeID int) ([]*model.Document, error)
+	DocumentURL(ctx context.Context, id int) (string, time.Time, error)
+	OpenDocument(ctx context.Context, id, tenantID int, expires int64, signature string) (model.Document, io.ReadCloser, error)
+	DeleteDocument(ctx context.Context, id int) error
+
+	UploadProfilePhoto(ctx context.Context, employeeID int, content io.Reader, size int64) (model.Employee, error)
+	ProfilePhotoURL(ctx context.Context, employeeID int, size model.PhotoSize) (string, error)
+	OpenProfilePhoto(ctx context.Context, employeeID, tenantID int, version string, size model.PhotoSize, signature string) (io.ReadCloser, error)
 }
 
 // Controller object to hold necessary reference to other dependencies
 type Controller struct {
-	storage         storage.Storage
-	logger          zerolog.Logger
-	employeeStorage storage.EmployeeDatabase
-	env             *environment.Env
-	middleware      *middleware.Middleware
+	storage           storage.Storage
+	logger            zerolog.Logger
+	employeeStorage   storage.EmployeeDatabase
+	departmentStorage storage.DepartmentDatabase
+	userStorage       storage.UserDatabase
+	apiKeyStorage     storage.APIKeyDatabase
+	sessionStorage    storage.SessionDatabase
+	auditStorage      storage.AuditDatabase
+	personalData      storage.PersonalDataDatabase
+	documentStorage   storage.DocumentDatabase
+	config            *config.Config
+	middleware        *middleware.Middleware
+	authorizer        authorizer
+	// urlSecret signs the document download and profile photo URLs
+	urlSecret []byte
 }
 
 // New creates a new instance of Controller
@@ -43,13 +92,35 @@ func New(z zerolog.Logger, s *storage.Storage, m *middleware.Middleware) *Operat
 	l := z.With().Str(helper.LogStrKeyModule, packageName).Logger()
 	// init all storage layer here
 	employee := storage.NewEmployee(s)
+	department := storage.NewDepartment(s)
+	user := storage.NewUser(s)
+	apiKey := storage.NewAPIKey(s)
+	session := storage.NewSession(s)
+	audit := storage.NewAudit(s)
+	personalData := storage.NewPersonalData(s)
+	document := storage.NewDocument(s)
 
 	ctrl := &Controller{
-		storage:         *s,
-		logger:          l,
-		employeeStorage: *employee,
-		env:             s.Env,
-		middleware:      m,
+		storage:           *s,
+		logger:            l,
+		employeeStorage:   *employee,
+		departmentStorage: *department,
+		userStorage:       *user,
+		apiKeyStorage:     *apiKey,
+		sessionStorage:    *session,
+		auditStorage:      *audit,
+		personalData:      *personalData,
+		documentStorage:   *document,
+		config:            s.Config,
+		middleware:        m,
+		urlSecret:         []byte(s.Config.Documents.URLSecret),
+	}
+	if s.Config.Documents.URLSecret == "" {
+		l.Warn().Msg("no document URL secret configured (DOCUMENTS_URL_SECRET), download and photo URLs only work on this instance until it restarts")
+		ctrl.urlSecret = make([]byte, 32)
+		if _, err := rand.Read(ctrl.urlSecret); err != nil {
+			panic(err)
+		}
 	}
 
 	op := Operations(ctrl)
@@ -60,3 +131,32 @@ func New(z zerolog.Logger, s *storage.Storage, m *middleware.Middleware) *Operat
 func (c *Controller) Middleware() *middleware.Middleware {
 	return c.middleware
 }
+
+// forgetUser makes the authentication see the changed user on its next request
+func (c *Controller) forgetUser(userID int) {
+	if c.middleware != nil {
+		c.middleware.ForgetUser(userID)
+	}
+}
+
+// requireAdministrator returns the caller when they are an administrator
+func (c *Controller) requireAdministrator(ctx context.Context) (*model.User, error) {
+	user, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.Kind != model.KindAdministrator {
+		return nil, storage.ErrUnauthorizedAccess
+	}
+	return user, nil
+}
+
+// audit records entry as made by actor, the change it describes is already made
+func (c *Controller) audit(ctx context.Context, actor *model.User, entry model.AuditEntry) error {
+	entry.ActorID = actor.ID
+	if _, err := c.auditStorage.AddAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	logging.Method(ctx, c.logger, "audit").Info().Msgf("audit: %s %s by user %d", entry.Action, entry.Detail, actor.ID)
+	return nil
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+
+	"employee-management-system/model"
+)
+
+// AddDepartment returns the created Department, only administrators create departments
+func (c *Controller) AddDepartment(ctx context.Context, department model.Department) (model.Department, error) {
+	if err := c.canManageDepartments(ctx); err != nil {
+		return model.Department{}, err
+	}
+	return c.departmentStorage.AddDepartment(ctx, department)
+}
+
+// GetDepartmentByID returns a Department by id supplied
+func (c *Controller) GetDepartmentByID(ctx context.Context, id int) (model.Department, error) {
+	return c.departmentStorage.GetDepartmentByID(ctx, id)
+}
+
+// GetAllDepartments returns all Departments
+func (c *Controller) GetAllDepartments(ctx context.Context) ([]*model.Department, error) {
+	return c.departmentStorage.GetAllDepartments(ctx)
+}
+
+// UpdateDepartmentByID for update, only administrators rename departments
+func (c *Controller) UpdateDepartmentByID(ctx context.Context, id int, department model.Department) (model.Department, error) {
+	if err := c.canManageDepartments(ctx); err != nil {
+		return model.Department{}, err
+	}
+	return c.departmentStorage.UpdateDepartmentByID(ctx, id, department)
+}
+
+// DeleteDepartmentByID for delete, only administrators delete departments
+func (c *Controller) DeleteDepartmentByID(ctx context.Context, id int) error {
+	if err := c.canManageDepartments(ctx); err != nil {
+		return err
+	}
+	return c.departmentStorage.DeleteDepartmentByID(ctx, id)
+}
+
+func (c *Controller) canManageDepartments(ctx context.Context) error {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return err
+	}
+	return c.authorizer.canManage(user)
+}
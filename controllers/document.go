@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/storage"
+)
+
+// documentContentPath is where the REST API serves the content of a document to signed URLs
+const documentContentPath = "/api/v1/documents/%d/content"
+
+// documentContentTypes are the accepted uploads, detected from the content rather than trusted
+// from the client
+var documentContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// UploadDocument stores a document about an employee, size bytes read from content. Administrators
+// upload for everyone, staff for their own record.
+func (c *Controller) UploadDocument(ctx context.Context, employeeID int, kind model.DocumentKind, fileName string, content io.Reader, size int64) (model.Document, error) {
+	user, employee, err := c.documentOwner(ctx, employeeID)
+	if err != nil {
+		return model.Document{}, err
+	}
+	if !kind.Valid() {
+		return model.Document{}, apierror.BadInput("unknown document kind %q", kind)
+	}
+	fileName = path.Base(strings.ReplaceAll(fileName, `\`, "/"))
+	if fileName == "." || fileName == "/" || len(fileName) > 255 {
+		return model.Document{}, apierror.BadInput("invalid file name")
+	}
+	if maxSize := int64(c.config.Documents.MaxSize); size <= 0 || size > maxSize {
+		return model.Document{}, apierror.BadInput("documents must be between 1 and %d bytes", maxSize)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return model.Document{}, err
+	}
+	head = head[:n]
+	if int64(n) > size {
+		return model.Document{}, apierror.BadInput("document is larger than its declared size of %d bytes", size)
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !documentContentTypes[contentType] {
+		return model.Document{}, apierror.BadInput("documents must be PDF, JPEG or PNG, not %s", contentType)
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return model.Document{}, err
+	}
+	document := model.Document{
+		EmployeeID:  employee.ID,
+		Kind:        kind,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  fmt.Sprintf("%d/%d/%s", tenantID, employee.ID, hex.EncodeToString(suffix)),
+		UploadedBy:  user.ID,
+	}
+	checksum := sha256.New()
+	body := io.TeeReader(io.LimitReader(io.MultiReader(bytes.NewReader(head), content), size), checksum)
+	if err := c.storage.Blobs.Put(ctx, document.StorageKey, body, size); err != nil {
+		logging.Method(ctx, c.logger, "UploadDocument").Err(err).Msgf("UploadDocument: storing %s: %v", document.StorageKey, err)
+		return model.Document{}, storage.ErrRecordCreatingFailed
+	}
+	if n, _ := content.Read(make([]byte, 1)); n > 0 {
+		c.deleteBlob(ctx, document.StorageKey)
+		return model.Document{}, apierror.BadInput("document is larger than its declared size of %d bytes", size)
+	}
+	document.Checksum = hex.EncodeToString(checksum.Sum(nil))
+
+	added, err := c.documentStorage.AddDocument(ctx, document)
+	if err != nil {
+		c.deleteBlob(ctx, document.StorageKey)
+		return model.Document{}, err
+	}
+	document = added
+	return document, c.audit(ctx, user, model.AuditEntry{
+		Action:            model.AuditDocumentUploaded,
+		SubjectUserID:     employee.UserID,
+		SubjectEmployeeID: employee.ID,
+		Detail:            fmt.Sprintf("%s document %d", kind, document.ID),
+	})
+}
+
+// GetDocuments returns the documents of an employee, to administrators and to the employee
+func (c *Controller) GetDocuments(ctx context.Context, employeeID int) ([]*model.Document, error) {
+	if _, _, err := c.documentOwner(ctx, employeeID); err != nil {
+		return nil, err
+	}
+	return c.documentStorage.GetDocumentsByEmployeeID(ctx, employeeID)
+}
+
+// DocumentURL returns a URL downloading the document without further credentials until it expires,
+// to administrators and to the employee
+func (c *Controller) DocumentURL(ctx context.Context, id int) (string, time.Time, error) {
+	if _, err := c.authorizer.caller(ctx); err != nil {
+		return "", time.Time{}, err
+	}
+	document, err := c.documentStorage.GetDocumentByID(ctx, id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if _, _, err := c.documentOwner(ctx, document.EmployeeID); err != nil {
+		return "", time.Time{}, err
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	expiresAt := time.Now().Add(c.config.Documents.URLExpiry.Duration()).Truncate(time.Second)
+	query := url.Values{
+		"tenant":    {strconv.Itoa(tenantID)},
+		"expires":   {strconv.FormatInt(expiresAt.Unix(), 10)},
+		"signature": {c.documentSignature(tenantID, id, expiresAt.Unix())},
+	}
+	return fmt.Sprintf(documentContentPath, id) + "?" + query.Encode(), expiresAt, nil
+}
+
+// OpenDocument returns the document a URL of DocumentURL names and its content, which the caller
+// closes. The signature stands in for the caller, so none is required.
+func (c *Controller) OpenDocument(ctx context.Context, id, tenantID int, expires int64, signature string) (model.Document, io.ReadCloser, error) {
+	if !hmac.Equal([]byte(signature), []byte(c.documentSignature(tenantID, id, expires))) || time.Now().Unix() > expires {
+		return model.Document{}, nil, storage.ErrUnauthorizedAccess
+	}
+
+	ctx = tenant.WithID(ctx, tenantID)
+	document, err := c.documentStorage.GetDocumentByID(ctx, id)
+	if err != nil {
+		return model.Document{}, nil, err
+	}
+	content, err := c.storage.Blobs.Get(ctx, document.StorageKey)
+	if err != nil {
+		logging.Method(ctx, c.logger, "OpenDocument").Err(err).Msgf("OpenDocument: reading %s: %v", document.StorageKey, err)
+		return model.Document{}, nil, storage.ErrRecordNotFound
+	}
+	return document, content, nil
+}
+
+// DeleteDocument removes a document and its content, only administrators delete documents
+func (c *Controller) DeleteDocument(ctx context.Context, id int) error {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.authorizer.canManage(user); err != nil {
+		return err
+	}
+	document, err := c.documentStorage.GetDocumentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, document.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if err := c.deleteDocument(ctx, document); err != nil {
+		return err
+	}
+	return c.audit(ctx, user, model.AuditEntry{
+		Action:            model.AuditDocumentDeleted,
+		SubjectUserID:     employee.UserID,
+		SubjectEmployeeID: employee.ID,
+		Detail:            fmt.Sprintf("%s document %d", document.Kind, document.ID),
+	})
+}
+
+// documentOwner returns the caller and the employee whose documents it acts on, when it may
+func (c *Controller) documentOwner(ctx context.Context, employeeID int) (*model.User, model.Employee, error) {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return nil, model.Employee{}, err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return nil, model.Employee{}, err
+	}
+	if err := c.authorizer.canAccessDocuments(user, employee); err != nil {
+		return nil, model.Employee{}, err
+	}
+	return user, employee, nil
+}
+
+// deleteDocument removes the row then the content, a content left behind is only logged
+func (c *Controller) deleteDocument(ctx context.Context, document model.Document) error {
+	if err := c.documentStorage.DeleteDocumentByID(ctx, document.ID); err != nil {
+		return err
+	}
+	c.deleteBlob(ctx, document.StorageKey)
+	return nil
+}
+
+func (c *Controller) deleteBlob(ctx context.Context, key string) {
+	if err := c.storage.Blobs.Delete(ctx, key); err != nil {
+		logging.Method(ctx, c.logger, "deleteBlob").Err(err).Msgf("deleteBlob: %s left behind: %v", key, err)
+	}
+}
+
+// documentSignature authenticates a download URL of the document of the tenant until expires
+func (c *Controller) documentSignature(tenantID, id int, expires int64) string {
+	mac := hmac.New(sha256.New, c.urlSecret)
+	fmt.Fprintf(mac, "%d:%d:%d", tenantID, id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
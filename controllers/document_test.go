@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/pkg/tenant"
+)
+
+func Test_UploadDocument_Size(t *testing.T) {
+	c := *New(zerolog.Nop(), newSQLiteStorage(t), nil)
+	ctx := middleware.ContextWithUser(tenant.WithID(context.Background(), tenant.DefaultID), &model.User{Kind: model.KindAdministrator})
+	employee, err := c.AddEmployee(ctx, model.Employee{FirstName: "Jane", LastName: "Doe", Email: "jane@company.com"})
+	require.NoError(t, err)
+
+	short, long := "%PDF-1.4\n"+strings.Repeat("x", 100), "%PDF-1.4\n"+strings.Repeat("x", 700)
+	// more than declared, within the bytes read to detect the type and beyond them
+	for content, size := range map[string]int64{short: 8, long: 600} {
+		_, err = c.UploadDocument(ctx, employee.ID, model.DocumentKindOther, "note.pdf", strings.NewReader(content), size)
+		var badInput *apierror.Error
+		require.ErrorAs(t, err, &badInput, size)
+	}
+
+	document, err := c.UploadDocument(ctx, employee.ID, model.DocumentKindOther, "note.pdf", strings.NewReader(short), int64(len(short)))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(short)), document.Size)
+}
@@ -4,34 +4,108 @@ import (
 	"context"
 
 	"employee-management-system/model"
+	"employee-management-system/model/pagination"
 )
 
-// AddEmployee returns an Employee
+// AddEmployee returns an Employee, only administrators add employees
 func (c *Controller) AddEmployee(ctx context.Context, employee model.Employee) (model.Employee, error) {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	if err := c.authorizer.canManage(user); err != nil {
+		return model.Employee{}, err
+	}
 	return c.employeeStorage.AddEmployee(ctx, employee)
 }
 
-// GetEmployeeByID returns an Employee by id supplied
+// GetEmployeeByID returns an Employee by id supplied, staff get the directory view of others
 func (c *Controller) GetEmployeeByID(ctx context.Context, ID int) (model.Employee, error) {
-	return c.employeeStorage.GetEmployeeByID(ctx, ID)
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, ID)
+	if err != nil {
+		return employee, err
+	}
+	return c.authorizer.view(user, employee), nil
 }
 
-// GetEmployeeByContext for getting employee
+// GetEmployeeByContext for getting employee of a user, staff get the directory view of others
 func (c *Controller) GetEmployeeByContext(ctx context.Context, userID int) (model.Employee, error) {
-	return c.employeeStorage.GetEmployeeByContext(ctx, userID)
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByContext(ctx, userID)
+	if err != nil {
+		return employee, err
+	}
+	return c.authorizer.view(user, employee), nil
 }
 
-// GetAllEmployees returns all Employees
+// GetAllEmployees returns all Employees, staff get the directory view of others
 func (c *Controller) GetAllEmployees(ctx context.Context) ([]*model.Employee, error) {
-	return c.employeeStorage.GetAllEmployees(ctx)
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := c.employeeStorage.GetAllEmployees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.authorizer.viewAll(user, employees), nil
 }
 
-// UpdateEmployeeByID for update
+// ListEmployees returns a page of Employees matching filter, staff get the directory view of others
+func (c *Controller) ListEmployees(ctx context.Context, filter model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error) {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return nil, pagination.PageInfo{}, err
+	}
+	filter.SearchEmail = c.authorizer.canSearchEmails(user)
+	employees, info, err := c.employeeStorage.ListEmployees(ctx, filter, page)
+	if err != nil {
+		return nil, info, err
+	}
+	return c.authorizer.viewAll(user, employees), info, nil
+}
+
+// UpdateEmployeeByID for update, staff only update the personal fields of their own record
 func (c *Controller) UpdateEmployeeByID(ctx context.Context, id int, employee model.Employee) (model.Employee, error) {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	stored, err := c.employeeStorage.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	if err := c.authorizer.canUpdate(user, stored, employee); err != nil {
+		return model.Employee{}, err
+	}
 	return c.employeeStorage.UpdateEmployeeByID(ctx, id, employee)
 }
 
-// DeleteEmployeeByID for delete
+// DeleteEmployeeByID for delete, only administrators delete employees. Its profile photo goes with it.
 func (c *Controller) DeleteEmployeeByID(ctx context.Context, id int) error {
-	return c.employeeStorage.DeleteEmployeeByID(ctx, id)
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.authorizer.canManage(user); err != nil {
+		return err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := c.employeeStorage.DeleteEmployeeByID(ctx, id); err != nil {
+		return err
+	}
+	if employee.PhotoKey != "" {
+		c.deletePhoto(ctx, employee.PhotoKey)
+	}
+	return nil
 }
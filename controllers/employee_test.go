@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/blob"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/database"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/pkg/migrate"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/storage"
+)
+
+// newSQLiteStorage returns storage over a migrated SQLite database, with documents kept in a
+// temporary directory
+func newSQLiteStorage(t *testing.T) *storage.Storage {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Database = config.Database{Driver: config.DatabaseDriverSQLite, Name: "file:" + filepath.Join(t.TempDir(), "controller.db") + "?_foreign_keys=on"}
+	cfg.Documents.URLSecret = "secret"
+	db, err := database.Open(cfg.Database)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := migrate.New(db, cfg.Database.Driver)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background(), time.Second))
+	gormDB, err := gorm.Open(sqlite.Dialector{Conn: db}, &gorm.Config{})
+	require.NoError(t, err)
+
+	s := storage.NewFromDB(gormDB)
+	s.Config = &cfg
+	s.Blobs, err = blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	return s
+}
+
+func Test_ListEmployees_SearchEmail(t *testing.T) {
+	c := *New(zerolog.Nop(), newSQLiteStorage(t), nil)
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	admin := middleware.ContextWithUser(ctx, &model.User{ID: 1, Kind: model.KindAdministrator})
+	staff := middleware.ContextWithUser(ctx, &model.User{ID: 2, Kind: model.KindStaff})
+
+	_, err := c.AddEmployee(admin, model.Employee{FirstName: "Jane", LastName: "Doe", Email: "jane@company.com", Position: "engineer"})
+	require.NoError(t, err)
+	search := func(ctx context.Context, q string) []*model.Employee {
+		employees, _, err := c.ListEmployees(ctx, model.EmployeeFilter{Search: &q}, pagination.Page{})
+		require.NoError(t, err)
+		return employees
+	}
+
+	require.Len(t, search(admin, "jane@company.com"), 1)
+	// the directory view masks the address, so staff may not confirm it either
+	require.Empty(t, search(staff, "jane@company.com"))
+	require.Len(t, search(staff, "Doe"), 1)
+}
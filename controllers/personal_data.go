@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/logging"
+)
+
+// ExportPersonalData returns everything held about an employee and its user, only administrators
+// export it. Every export is recorded in the audit trail.
+func (c *Controller) ExportPersonalData(ctx context.Context, employeeID int) (model.PersonalData, error) {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return model.PersonalData{}, err
+	}
+
+	data, err := c.personalData.GetPersonalData(ctx, employeeID, time.Now())
+	if err != nil {
+		return model.PersonalData{}, err
+	}
+
+	entry := model.AuditEntry{
+		Action:            model.AuditPersonalDataExported,
+		SubjectEmployeeID: data.Employee.ID,
+		Detail:            fmt.Sprintf("employee %d", data.Employee.ID),
+	}
+	if data.User != nil {
+		entry.SubjectUserID = data.User.ID
+	}
+	return data, c.audit(ctx, admin, entry)
+}
+
+// ErasePersonalData anonymizes an employee and its user and deletes its photo and documents but
+// contracts, only administrators erase them. The erasure and its audit entry are stored together.
+func (c *Controller) ErasePersonalData(ctx context.Context, employeeID int) error {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := c.personalData.ErasePersonalData(ctx, employeeID, model.AuditEntry{
+		ActorID: admin.ID,
+		Detail:  fmt.Sprintf("employee %d", employeeID),
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	logging.Method(ctx, c.logger, "ErasePersonalData").Info().Msgf("audit: %s %s by user %d", entry.Action, entry.Detail, admin.ID)
+	if entry.SubjectUserID != 0 {
+		c.forgetUser(entry.SubjectUserID)
+	}
+	if employee.PhotoKey != "" {
+		c.deletePhoto(ctx, employee.PhotoKey)
+	}
+
+	// contracts are kept for legal retention, identity scans and other documents go
+	documents, err := c.documentStorage.GetDocumentsByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+	for _, document := range documents {
+		if document.Kind == model.DocumentKindContract {
+			continue
+		}
+		if err := c.deleteDocument(ctx, *document); err != nil {
+			return err
+		}
+	}
+	return nil
+}
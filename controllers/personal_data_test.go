@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/pkg/tenant"
+)
+
+func Test_ErasePersonalData_AuditTrail(t *testing.T) {
+	s := newSQLiteStorage(t)
+	c := *New(zerolog.Nop(), s, nil)
+
+	// the admin command line, no acting user is recorded
+	admin := &model.User{Kind: model.KindAdministrator}
+	ctx := middleware.ContextWithUser(tenant.WithID(context.Background(), tenant.DefaultID), admin)
+
+	user, err := c.CreateUser(ctx, "jane.doe", model.Password("secret"), model.KindStaff)
+	require.NoError(t, err)
+	employee, err := c.AddEmployee(ctx, model.Employee{UserID: user.ID, FirstName: "Jane", LastName: "Doe", Email: "jane@company.com", Position: "engineer"})
+	require.NoError(t, err)
+	upload := func(kind model.DocumentKind, fileName string) model.Document {
+		content := "%PDF-1.4\n"
+		document, err := c.UploadDocument(ctx, employee.ID, kind, fileName, strings.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		return document
+	}
+	upload(model.DocumentKindContract, "jane-doe-contract.pdf")
+	upload(model.DocumentKindIdentity, "jane-doe-passport.pdf")
+	require.NoError(t, c.DeleteDocument(ctx, upload(model.DocumentKindOther, "jane-doe-sick-note.pdf").ID))
+
+	require.NoError(t, c.ErasePersonalData(ctx, employee.ID))
+
+	var details []string
+	require.NoError(t, s.DB.Model(&model.AuditEntry{}).WithContext(ctx).Order("id").Pluck("detail", &details).Error)
+	require.Len(t, details, 6)
+	for _, detail := range details {
+		require.NotContains(t, detail, "jane")
+	}
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/pkg/thumbnail"
+	"employee-management-system/storage"
+)
+
+// photoContentPath is where the REST API serves profile photos to signed URLs
+const photoContentPath = "/api/v1/employees/%d/photo"
+
+// UploadProfilePhoto replaces the profile photo of an employee with the JPEG or PNG picture of size
+// bytes read from content, stored as a square thumbnail of every model.PhotoSize. Administrators
+// change every photo, staff their own.
+func (c *Controller) UploadProfilePhoto(ctx context.Context, employeeID int, content io.Reader, size int64) (model.Employee, error) {
+	user, err := c.authorizer.caller(ctx)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return model.Employee{}, err
+	}
+	if err := c.authorizer.canChangePhoto(user, employee); err != nil {
+		return model.Employee{}, err
+	}
+	if maxSize := int64(c.config.Documents.PhotoMaxSize); size <= 0 || size > maxSize {
+		return model.Employee{}, apierror.BadInput("photos must be between 1 and %d bytes", maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, size+1))
+	if err != nil {
+		return model.Employee{}, err
+	}
+	if int64(len(data)) != size {
+		return model.Employee{}, apierror.BadInput("photo is not of its declared size of %d bytes", size)
+	}
+	img, err := thumbnail.Decode(data)
+	if errors.Is(err, thumbnail.ErrTooLarge) {
+		return model.Employee{}, apierror.BadInput("photos must have at most %d pixels", thumbnail.MaxPixels)
+	}
+	if err != nil {
+		return model.Employee{}, apierror.BadInput("photos must be JPEG or PNG pictures")
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	version := make([]byte, 16)
+	if _, err := rand.Read(version); err != nil {
+		return model.Employee{}, err
+	}
+	photoKey := fmt.Sprintf("%d/%d/photos/%s", tenantID, employee.ID, hex.EncodeToString(version))
+	// largest first, every smaller size is scaled down from the one before
+	for i := len(model.PhotoSizes) - 1; i >= 0; i-- {
+		photoSize := model.PhotoSizes[i]
+		thumb := thumbnail.Square(img, photoSize.Pixels())
+		img = thumb
+
+		var buf bytes.Buffer
+		err := thumbnail.Encode(&buf, thumb)
+		if err == nil {
+			err = c.storage.Blobs.Put(ctx, photoBlobKey(photoKey, photoSize), &buf, int64(buf.Len()))
+		}
+		if err != nil {
+			logging.Method(ctx, c.logger, "UploadProfilePhoto").Err(err).Msgf("UploadProfilePhoto: storing %s: %v", photoKey, err)
+			c.deletePhoto(ctx, photoKey)
+			return model.Employee{}, storage.ErrRecordCreatingFailed
+		}
+	}
+
+	if err := c.employeeStorage.UpdateEmployeePhoto(ctx, employee.ID, photoKey); err != nil {
+		c.deletePhoto(ctx, photoKey)
+		return model.Employee{}, err
+	}
+	if employee.PhotoKey != "" {
+		c.deletePhoto(ctx, employee.PhotoKey)
+	}
+	employee.PhotoKey = photoKey
+	return employee, nil
+}
+
+// ProfilePhotoURL returns a URL of the profile photo of an employee at size, empty when it has
+// none. Photos are part of the directory, every signed in user sees them. The URL needs no further
+// credentials and changes with the photo, so it is cached for as long as clients like.
+func (c *Controller) ProfilePhotoURL(ctx context.Context, employeeID int, size model.PhotoSize) (string, error) {
+	if _, err := c.authorizer.caller(ctx); err != nil {
+		return "", err
+	}
+	if !size.Valid() {
+		return "", apierror.BadInput("unknown photo size %q", size)
+	}
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return "", err
+	}
+	if employee.PhotoKey == "" {
+		return "", nil
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	version := path.Base(employee.PhotoKey)
+	query := url.Values{
+		"size":      {string(size)},
+		"tenant":    {strconv.Itoa(tenantID)},
+		"version":   {version},
+		"signature": {c.photoSignature(tenantID, employee.ID, version, size)},
+	}
+	return fmt.Sprintf(photoContentPath, employee.ID) + "?" + query.Encode(), nil
+}
+
+// OpenProfilePhoto returns the content of the JPEG thumbnail a URL of ProfilePhotoURL names, which
+// the caller closes. The signature stands in for the caller, so none is required; a replaced photo
+// is no longer found.
+func (c *Controller) OpenProfilePhoto(ctx context.Context, employeeID, tenantID int, version string, size model.PhotoSize, signature string) (io.ReadCloser, error) {
+	if !hmac.Equal([]byte(signature), []byte(c.photoSignature(tenantID, employeeID, version, size))) {
+		return nil, storage.ErrUnauthorizedAccess
+	}
+	if !size.Valid() {
+		return nil, apierror.BadInput("unknown photo size %q", size)
+	}
+
+	ctx = tenant.WithID(ctx, tenantID)
+	employee, err := c.employeeStorage.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if employee.PhotoKey == "" || path.Base(employee.PhotoKey) != version {
+		return nil, storage.ErrRecordNotFound
+	}
+	content, err := c.storage.Blobs.Get(ctx, photoBlobKey(employee.PhotoKey, size))
+	if err != nil {
+		logging.Method(ctx, c.logger, "OpenProfilePhoto").Err(err).Msgf("OpenProfilePhoto: reading %s: %v", employee.PhotoKey, err)
+		return nil, storage.ErrRecordNotFound
+	}
+	return content, nil
+}
+
+// deletePhoto removes every thumbnail of the photo, those left behind are only logged
+func (c *Controller) deletePhoto(ctx context.Context, photoKey string) {
+	for _, size := range model.PhotoSizes {
+		c.deleteBlob(ctx, photoBlobKey(photoKey, size))
+	}
+}
+
+// photoSignature authenticates a URL of the thumbnail at size of the photo version of the employee
+// of the tenant
+func (c *Controller) photoSignature(tenantID, employeeID int, version string, size model.PhotoSize) string {
+	mac := hmac.New(sha256.New, c.urlSecret)
+	fmt.Fprintf(mac, "photo:%d:%d:%s:%s", tenantID, employeeID, version, size)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func photoBlobKey(photoKey string, size model.PhotoSize) string {
+	return photoKey + "/" + string(size) + ".jpg"
+}
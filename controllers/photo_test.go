@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/pkg/tenant"
+)
+
+func Test_UploadProfilePhoto_Size(t *testing.T) {
+	var picture bytes.Buffer
+	require.NoError(t, png.Encode(&picture, image.NewRGBA(image.Rect(0, 0, 8, 8))))
+
+	s := newSQLiteStorage(t)
+	// photos have their own limit, below the one of documents
+	s.Config.Documents.PhotoMaxSize = picture.Len() - 1
+	c := *New(zerolog.Nop(), s, nil)
+	ctx := middleware.ContextWithUser(tenant.WithID(context.Background(), tenant.DefaultID), &model.User{Kind: model.KindAdministrator})
+	employee, err := c.AddEmployee(ctx, model.Employee{FirstName: "Jane", LastName: "Doe", Email: "jane@company.com"})
+	require.NoError(t, err)
+
+	_, err = c.UploadProfilePhoto(ctx, employee.ID, bytes.NewReader(picture.Bytes()), int64(picture.Len()))
+	var badInput *apierror.Error
+	require.ErrorAs(t, err, &badInput)
+
+	s.Config.Documents.PhotoMaxSize = picture.Len()
+	employee, err = c.UploadProfilePhoto(ctx, employee.ID, bytes.NewReader(picture.Bytes()), int64(picture.Len()))
+	require.NoError(t, err)
+	require.NotEmpty(t, employee.PhotoKey)
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+// GetMySessions returns the active sessions of the signed in user
+func (c *Controller) GetMySessions(ctx context.Context) ([]*model.Session, error) {
+	user, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.sessionStorage.GetActiveSessionsByUserID(ctx, user.ID, time.Now())
+}
+
+// RevokeSession logs out a single session. Users may revoke their own sessions, administrators any
+// session of their tenant
+func (c *Controller) RevokeSession(ctx context.Context, id int) error {
+	user, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	session, err := c.sessionStorage.GetSessionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if session.UserID != user.ID && user.Kind != model.KindAdministrator {
+		// do not reveal that the session exists
+		return storage.ErrRecordNotFound
+	}
+
+	if err := c.sessionStorage.RevokeSessionByID(ctx, id, time.Now()); err != nil {
+		return err
+	}
+
+	logging.Method(ctx, c.logger, "RevokeSession").Info().Msgf("RevokeSession: session %d of user %d revoked by user %d", id, session.UserID, user.ID)
+	return nil
+}
+
+// RevokeAllSessions logs a user of the caller's tenant out everywhere, e.g. when the account is compromised
+func (c *Controller) RevokeAllSessions(ctx context.Context, userID int) (int64, error) {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// the user storage is scoped by tenant, users of other tenants are not found
+	user, err := c.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if user.ID == 0 {
+		return 0, storage.ErrRecordNotFound
+	}
+
+	count, err := c.sessionStorage.RevokeAllSessionsByUserID(ctx, user.ID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	logging.Method(ctx, c.logger, "RevokeAllSessions").Info().Msgf("RevokeAllSessions: %d sessions of user %d revoked by user %d", count, userID, admin.ID)
+	return count, nil
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/storage"
+)
+
+// CreateUser registers a user that signs in with a password
+func (c *Controller) CreateUser(ctx context.Context, userName string, password model.Password, kind model.Kind) (model.User, error) {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if _, err := c.userStorage.GetUserByUserName(ctx, userName); err == nil {
+		return model.User{}, storage.ErrDuplicateRecord
+	}
+
+	user, err := c.userStorage.Register(ctx, model.User{
+		UserName: &userName,
+		Password: password.Encrypt(),
+		Kind:     kind,
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	// the user is committed, failing now would make a retry fail on its name
+	if err := c.audit(ctx, admin, model.AuditEntry{
+		Action:        model.AuditUserCreated,
+		SubjectUserID: user.ID,
+		Detail:        fmt.Sprintf("user %d (%s)", user.ID, kind),
+	}); err != nil {
+		logging.Method(ctx, c.logger, "CreateUser").Err(err).Msgf("CreateUser: audit of user %d not recorded: %v", user.ID, err)
+	}
+	return user, nil
+}
+
+// ResetPassword replaces the password of a user and logs it out everywhere
+func (c *Controller) ResetPassword(ctx context.Context, userName string, password model.Password) error {
+	admin, user, err := c.adminAndUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+	if user.ServiceAccount {
+		// service accounts authenticate with API keys only
+		return storage.ErrUnauthorizedAccess
+	}
+
+	if err := c.userStorage.UpdatePassword(ctx, user.ID, password.Encrypt()); err != nil {
+		return err
+	}
+	c.forgetUser(user.ID)
+	if _, err := c.sessionStorage.RevokeAllSessionsByUserID(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+
+	return c.audit(ctx, admin, model.AuditEntry{
+		Action:        model.AuditPasswordReset,
+		SubjectUserID: user.ID,
+		Detail:        fmt.Sprintf("password of user %d", user.ID),
+	})
+}
+
+// SetUserKind changes the role of a user and logs it out everywhere, so no token outlives the old role
+func (c *Controller) SetUserKind(ctx context.Context, userName string, kind model.Kind) error {
+	admin, user, err := c.adminAndUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.userStorage.UpdateKind(ctx, user.ID, kind); err != nil {
+		return err
+	}
+	c.forgetUser(user.ID)
+	if _, err := c.sessionStorage.RevokeAllSessionsByUserID(ctx, user.ID, time.Now()); err != nil {
+		return err
+	}
+
+	return c.audit(ctx, admin, model.AuditEntry{
+		Action:        model.AuditRoleChanged,
+		SubjectUserID: user.ID,
+		Detail:        fmt.Sprintf("user %d from %s to %s", user.ID, user.Kind, kind),
+	})
+}
+
+// LockUser stops a user from signing in and logs it out everywhere
+func (c *Controller) LockUser(ctx context.Context, userName string) error {
+	admin, user, err := c.adminAndUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := c.userStorage.UpdateLockedAt(ctx, user.ID, &now); err != nil {
+		return err
+	}
+	c.forgetUser(user.ID)
+	if _, err := c.sessionStorage.RevokeAllSessionsByUserID(ctx, user.ID, now); err != nil {
+		return err
+	}
+
+	return c.audit(ctx, admin, model.AuditEntry{
+		Action:        model.AuditUserLocked,
+		SubjectUserID: user.ID,
+		Detail:        fmt.Sprintf("user %d", user.ID),
+	})
+}
+
+// UnlockUser lets a locked user sign in again
+func (c *Controller) UnlockUser(ctx context.Context, userName string) error {
+	admin, user, err := c.adminAndUser(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.userStorage.UpdateLockedAt(ctx, user.ID, nil); err != nil {
+		return err
+	}
+	c.forgetUser(user.ID)
+
+	return c.audit(ctx, admin, model.AuditEntry{
+		Action:        model.AuditUserUnlocked,
+		SubjectUserID: user.ID,
+		Detail:        fmt.Sprintf("user %d", user.ID),
+	})
+}
+
+// adminAndUser returns the calling administrator and the user named userName
+func (c *Controller) adminAndUser(ctx context.Context, userName string) (*model.User, model.User, error) {
+	admin, err := c.requireAdministrator(ctx)
+	if err != nil {
+		return nil, model.User{}, err
+	}
+
+	user, err := c.userStorage.GetUserByUserName(ctx, userName)
+	if err != nil {
+		return nil, model.User{}, err
+	}
+	return admin, user, nil
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+type (
+	fakeUsers struct {
+		storage.UserDatabase
+		user model.User
+	}
+
+	fakeSessions struct {
+		storage.SessionDatabase
+		revoked []int
+	}
+
+	fakeAudit struct {
+		storage.AuditDatabase
+		entries []model.AuditEntry
+		err     error
+	}
+)
+
+func (f *fakeUsers) GetUserByUserName(_ context.Context, userName string) (model.User, error) {
+	if f.user.UserName == nil || userName != *f.user.UserName {
+		return model.User{}, storage.ErrRecordNotFound
+	}
+	return f.user, nil
+}
+
+func (f *fakeUsers) Register(_ context.Context, user model.User) (model.User, error) {
+	user.ID = 2
+	f.user = user
+	return user, nil
+}
+
+func (f *fakeUsers) UpdateKind(_ context.Context, id int, kind model.Kind) error {
+	f.user.Kind = kind
+	return nil
+}
+
+func (f *fakeSessions) RevokeAllSessionsByUserID(_ context.Context, userID int, _ time.Time) (int64, error) {
+	f.revoked = append(f.revoked, userID)
+	return 1, nil
+}
+
+func (f *fakeAudit) AddAuditEntry(_ context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	if f.err != nil {
+		return model.AuditEntry{}, f.err
+	}
+	f.entries = append(f.entries, entry)
+	return entry, nil
+}
+
+func Test_SetUserKind(t *testing.T) {
+	name := "jane"
+	users := &fakeUsers{user: model.User{ID: 2, UserName: &name, Kind: model.KindAdministrator}}
+	sessions := &fakeSessions{}
+	audit := &fakeAudit{}
+	c := &Controller{logger: zerolog.Nop(), userStorage: users, sessionStorage: sessions, auditStorage: audit}
+
+	admin := &model.User{ID: 1, Kind: model.KindAdministrator}
+	ctx := middleware.ContextWithUser(context.Background(), admin)
+
+	// tokens issued for the old role stop working at once
+	require.NoError(t, c.SetUserKind(ctx, "jane", model.KindStaff))
+	require.Equal(t, model.Kind(model.KindStaff), users.user.Kind)
+	require.Equal(t, []int{2}, sessions.revoked)
+	require.Len(t, audit.entries, 1)
+	require.Equal(t, model.AuditRoleChanged, audit.entries[0].Action)
+
+	staff := middleware.ContextWithUser(context.Background(), &model.User{ID: 3, Kind: model.KindStaff})
+	require.ErrorIs(t, c.SetUserKind(staff, "jane", model.KindAdministrator), storage.ErrUnauthorizedAccess)
+	require.Equal(t, []int{2}, sessions.revoked)
+}
+
+func Test_CreateUser_AuditFailure(t *testing.T) {
+	users := &fakeUsers{}
+	c := &Controller{logger: zerolog.Nop(), userStorage: users, auditStorage: &fakeAudit{err: storage.ErrRecordCreatingFailed}}
+	ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: 1, Kind: model.KindAdministrator})
+
+	// the user is committed, so its creation succeeds even though the audit entry is lost
+	user, err := c.CreateUser(ctx, "jane", model.Password("secret"), model.KindStaff)
+	require.NoError(t, err)
+	require.Equal(t, 2, user.ID)
+	require.Equal(t, "jane", *users.user.UserName)
+}
@@ -1,6 +1,5 @@
 #!/bin/sh
 
-echo "change to work dir"
-cd terminal/goose || return
-echo "execute commands and flags"
-go run main.go "$1" "$2" "$3" # allowing only 5 args for now
\ No newline at end of file
+# runs the goose CLI from the repository root, e.g. ./goose.sh status or ./goose.sh up --dry-run
+cd "$(dirname "$0")" || exit 1
+go run ./terminal/goose "$@"
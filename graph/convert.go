@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"employee-management-system/graph/model"
+	appModel "employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+)
+
+const dateLayout = "2006-01-02"
+
+func toRole(kind appModel.Kind) model.Role {
+	if kind == appModel.KindAdministrator {
+		return model.RoleAdministrator
+	}
+	return model.RoleStaff
+}
+
+func fromRole(role model.Role) appModel.Kind {
+	if role == model.RoleAdministrator {
+		return appModel.KindAdministrator
+	}
+	return appModel.KindStaff
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+func parseTime(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func toAPIKey(key appModel.APIKey) *model.APIKey {
+	apiKey := &model.APIKey{
+		ID:         strconv.Itoa(key.ID),
+		Role:       toRole(key.User.Kind),
+		Prefix:     key.Prefix,
+		ExpiresAt:  formatTime(key.ExpiresAt),
+		LastUsedAt: formatTime(key.LastUsedAt),
+		RevokedAt:  formatTime(key.RevokedAt),
+		CreatedAt:  key.CreatedAt.Format(time.RFC3339),
+	}
+	if key.User.UserName != nil {
+		apiKey.ServiceAccount = *key.User.UserName
+	}
+	return apiKey
+}
+
+func toSession(session appModel.Session, currentID int) *model.Session {
+	return &model.Session{
+		ID:         strconv.Itoa(session.ID),
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+		LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+		ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+		Current:    session.ID == currentID,
+	}
+}
+
+func toDocumentKind(kind appModel.DocumentKind) model.DocumentKind {
+	return model.DocumentKind(strings.ToUpper(string(kind)))
+}
+
+func fromDocumentKind(kind model.DocumentKind) appModel.DocumentKind {
+	return appModel.DocumentKind(strings.ToLower(string(kind)))
+}
+
+func toDocument(document appModel.Document) *model.Document {
+	d := &model.Document{
+		ID:          strconv.Itoa(document.ID),
+		EmployeeID:  strconv.Itoa(document.EmployeeID),
+		Kind:        toDocumentKind(document.Kind),
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		Size:        int(document.Size),
+		Checksum:    document.Checksum,
+		CreatedAt:   document.CreatedAt.Format(time.RFC3339),
+	}
+	if document.UploadedBy != 0 {
+		uploadedBy := strconv.Itoa(document.UploadedBy)
+		d.UploadedBy = &uploadedBy
+	}
+	return d
+}
+
+func fromPhotoSize(size model.PhotoSize) appModel.PhotoSize {
+	return appModel.PhotoSize(strings.ToLower(string(size)))
+}
+
+func toEmployee(employee appModel.Employee) *model.Employee {
+	e := &model.Employee{
+		ID:        strconv.Itoa(employee.ID),
+		FirstName: employee.FirstName,
+		LastName:  employee.LastName,
+		Position:  employee.Position,
+	}
+	if employee.UserID != 0 {
+		e.UserID = strconv.Itoa(employee.UserID)
+	}
+	if employee.Email != "" {
+		e.Email = &employee.Email
+	}
+	if !employee.Dob.IsZero() {
+		dob := employee.Dob.Format(dateLayout)
+		e.Dob = &dob
+	}
+	if employee.DepartmentID != 0 {
+		departmentID := strconv.Itoa(employee.DepartmentID)
+		e.DepartmentID = &departmentID
+	}
+	return e
+}
+
+func parseID(field, value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, apierror.BadInput("%s must be a positive integer", field)
+	}
+	return id, nil
+}
+
+func parseDate(field, value string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, apierror.BadInput("%s must be a date formatted YYYY-MM-DD", field)
+	}
+	return t, nil
+}
+
+func fromCreateEmployeeInput(input model.CreateEmployeeInput) (appModel.Employee, error) {
+	userID, err := parseID("userID", input.UserID)
+	if err != nil {
+		return appModel.Employee{}, err
+	}
+	dob, err := parseDate("dob", input.Dob)
+	if err != nil {
+		return appModel.Employee{}, err
+	}
+
+	employee := appModel.Employee{
+		UserID:    userID,
+		FirstName: input.FirstName,
+		LastName:  input.LastName,
+		Email:     input.Email,
+		Dob:       dob,
+	}
+	if input.DepartmentID != nil {
+		if employee.DepartmentID, err = parseID("departmentID", *input.DepartmentID); err != nil {
+			return appModel.Employee{}, err
+		}
+	}
+	if input.Position != nil {
+		employee.Position = *input.Position
+	}
+	return employee, nil
+}
+
+// fromUpdateEmployeeInput returns the changes of input, zero values are not written so only the
+// supplied fields change
+func fromUpdateEmployeeInput(input model.UpdateEmployeeInput) (appModel.Employee, error) {
+	var (
+		changes appModel.Employee
+		err     error
+	)
+	if input.FirstName != nil {
+		changes.FirstName = *input.FirstName
+	}
+	if input.LastName != nil {
+		changes.LastName = *input.LastName
+	}
+	if input.Dob != nil {
+		if changes.Dob, err = parseDate("dob", *input.Dob); err != nil {
+			return appModel.Employee{}, err
+		}
+	}
+	if input.DepartmentID != nil {
+		if changes.DepartmentID, err = parseID("departmentID", *input.DepartmentID); err != nil {
+			return appModel.Employee{}, err
+		}
+	}
+	if input.Position != nil {
+		changes.Position = *input.Position
+	}
+	return changes, nil
+}
@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"employee-management-system/pkg/apierror"
+)
+
+// errorPresenter adds the API error code to resolver errors that do not carry one yet
+func errorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	if _, ok := presented.Extensions["code"]; !ok {
+		errcode.Set(presented, apierror.Code(err))
+	}
+	return presented
+}
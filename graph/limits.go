@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errDepthLimitCode = "DEPTH_LIMIT_EXCEEDED"
+)
+
+// Complexity estimates the cost of every field returning a list as listMultiplier times the cost of
+// its elements, the other fields keep the default cost of one plus their children. List fields added
+// to the schema must be added here too.
+func Complexity(listMultiplier int) ComplexityRoot {
+	list := func(childComplexity int) int {
+		return 1 + childComplexity*listMultiplier
+	}
+
+	var c ComplexityRoot
+	c.Query.GetAllEmployees = list
+	c.Query.APIKeys = list
+	c.Query.MySessions = list
+	c.Query.Documents = func(childComplexity int, _ string) int {
+		return list(childComplexity)
+	}
+	return c
+}
+
+// DepthLimit rejects operations nesting selections deeper than Limit. Introspection fields are not
+// counted, the standard introspection query is deep but cheap.
+type DepthLimit struct {
+	Limit int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = DepthLimit{}
+
+// ExtensionName of the gqlgen extension
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+// Validate the extension against the schema, there is nothing to check
+func (DepthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext measures the operation once it has been parsed and validated
+func (d DepthLimit) MutateOperationContext(_ context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+	if depth := selectionDepth(rc.Operation.SelectionSet); depth > d.Limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Limit)
+		errcode.Set(err, errDepthLimitCode)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns the number of nested field levels, fragments add no level of their own
+func selectionDepth(set ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range set {
+		depth := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if len(s.Name) > 1 && s.Name[:2] == "__" {
+				continue
+			}
+			depth = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+	return deepest
+}
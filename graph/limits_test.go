@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appModel "employee-management-system/model"
+	"employee-management-system/pkg/config"
+)
+
+const employeesQuery = "query Employees { getAllEmployees { id firstName } }"
+
+func newTestServer(t *testing.T, cfg config.GraphQL) http.Handler {
+	srv, err := NewServer(NewExecutableSchema(Config{
+		Resolvers:  New(nil, &fakeOperations{employees: map[int]appModel.Employee{1: henry}}),
+		Complexity: Complexity(cfg.ListMultiplier),
+		Directives: Directives(),
+	}), cfg, 1<<20)
+	require.NoError(t, err)
+	return srv
+}
+
+// errorCodes posts body and returns the extension codes of the errors answered
+func errorCodes(t *testing.T, h http.Handler, body string) []string {
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var resp struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	codes := []string{}
+	for _, e := range resp.Errors {
+		code, _ := e.Extensions["code"].(string)
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func Test_ComplexityLimit(t *testing.T) {
+	cfg := config.Default().GraphQL
+	cfg.ComplexityLimit = 40
+	h := newTestServer(t, cfg)
+
+	// 1 + 2 fields * 20 elements
+	require.Contains(t, errorCodes(t, h, `{"query":"`+employeesQuery+`"}`), "COMPLEXITY_LIMIT_EXCEEDED")
+	require.NotContains(t, errorCodes(t, h, `{"query":"{ getEmployee(id: \"1\") { id } }"}`), "COMPLEXITY_LIMIT_EXCEEDED")
+}
+
+func Test_DepthLimit(t *testing.T) {
+	cfg := config.Default().GraphQL
+	cfg.DepthLimit = 1
+	h := newTestServer(t, cfg)
+
+	require.Equal(t, []string{errDepthLimitCode}, errorCodes(t, h,
+		`{"query":"query { ...Employees } fragment Employees on Query { getAllEmployees { id } }"}`))
+	require.NotContains(t, errorCodes(t, h, `{"query":"{ __schema { types { fields { type { name } } } } }"}`), errDepthLimitCode)
+}
+
+func Test_PersistedQueriesOnly(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "queries.json")
+	allowList, err := json.Marshal(map[string]string{queryHash(employeesQuery): employeesQuery})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, allowList, 0o600))
+
+	cfg := config.Default().GraphQL
+	cfg.PersistedQueriesOnly = true
+	cfg.PersistedQueriesFile = file
+	h := newTestServer(t, cfg)
+
+	require.Equal(t, []string{errPersistedQueryNotAllowedCode}, errorCodes(t, h, `{"query":"{ mySessions { id } }"}`))
+	hashOnly := `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + queryHash(employeesQuery) + `"}}}`
+	require.NotContains(t, errorCodes(t, h, hashOnly), errPersistedQueryNotAllowedCode)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"0000":"{ mySessions { id } }"}`), 0o600))
+	_, err = LoadPersistedQueries(file)
+	require.ErrorContains(t, err, "does not match")
+}
@@ -2,56 +2,127 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type APIKey struct {
+	ID             string  `json:"id"`
+	ServiceAccount string  `json:"serviceAccount"`
+	Role           Role    `json:"role"`
+	Prefix         string  `json:"prefix"`
+	ExpiresAt      *string `json:"expiresAt,omitempty"`
+	LastUsedAt     *string `json:"lastUsedAt,omitempty"`
+	RevokedAt      *string `json:"revokedAt,omitempty"`
+	CreatedAt      string  `json:"createdAt"`
+}
+
 type AuthResponse struct {
 	Token              *string `json:"token,omitempty"`
 	Refresh            *string `json:"refresh,omitempty"`
 	User               *User   `json:"user,omitempty"`
 	AccessTokenExpiry  *string `json:"accessTokenExpiry,omitempty"`
 	RefreshTokenExpiry *string `json:"refreshTokenExpiry,omitempty"`
+	// When true no tokens are issued yet, call verifyTwoFactor with the challengeToken
+	TwoFactorRequired bool `json:"twoFactorRequired"`
+	// When true the role requires two-factor authentication, call enrollTwoFactor with the challengeToken first
+	TwoFactorEnrollmentRequired bool    `json:"twoFactorEnrollmentRequired"`
+	ChallengeToken              *string `json:"challengeToken,omitempty"`
+}
+
+type CreateAPIKeyInput struct {
+	ServiceAccount string  `json:"serviceAccount"`
+	Role           Role    `json:"role"`
+	ExpiresAt      *string `json:"expiresAt,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"apiKey"`
+	// The plain text key, it is only ever shown in this response
+	Key string `json:"key"`
 }
 
+// The user the employee signs in as must exist, dob is formatted YYYY-MM-DD
 type CreateEmployeeInput struct {
-	FirstName    string `json:"firstName"`
-	LastName     string `json:"lastName"`
-	UserName     string `json:"userName"`
-	Password     string `json:"password"`
-	Email        string `json:"email"`
-	Dob          string `json:"dob"`
-	DepartmentID string `json:"departmentID"`
-	Position     string `json:"position"`
+	UserID       string  `json:"userID"`
+	FirstName    string  `json:"firstName"`
+	LastName     string  `json:"lastName"`
+	Email        string  `json:"email"`
+	Dob          string  `json:"dob"`
+	DepartmentID *string `json:"departmentID,omitempty"`
+	Position     *string `json:"position,omitempty"`
 }
 
 type DeleteEmployeeResponse struct {
 	DeleteEmployeeID string `json:"deleteEmployeeId"`
 }
 
+type Document struct {
+	ID          string       `json:"id"`
+	EmployeeID  string       `json:"employeeID"`
+	Kind        DocumentKind `json:"kind"`
+	FileName    string       `json:"fileName"`
+	ContentType string       `json:"contentType"`
+	Size        int          `json:"size"`
+	// Hex encoded SHA-256 of the content
+	Checksum   string  `json:"checksum"`
+	UploadedBy *string `json:"uploadedBy,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+	// A URL downloading the content without credentials until it expires
+	Download *DocumentDownload `json:"download"`
+}
+
+type DocumentDownload struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
 type Employee struct {
 	ID           string  `json:"id"`
 	UserID       string  `json:"userID"`
 	FirstName    string  `json:"firstName"`
 	LastName     string  `json:"lastName"`
-	Password     string  `json:"password"`
-	Email        string  `json:"email"`
-	Dob          string  `json:"dob"`
+	Email        *string `json:"email,omitempty"`
+	Dob          *string `json:"dob,omitempty"`
 	DepartmentID *string `json:"departmentID,omitempty"`
 	Position     string  `json:"position"`
+	// A URL of the profile photo as a square JPEG of size, null without a photo. It changes with the photo and may be cached
+	PhotoURL *string `json:"photoUrl,omitempty"`
+}
+
+type Session struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"userAgent"`
+	IPAddress  string `json:"ipAddress"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	// True for the session of the token making this request
+	Current bool `json:"current"`
+}
+
+type TwoFactorEnrollment struct {
+	// otpauth:// URI to show as a QR code in authenticator apps
+	ProvisioningURI string `json:"provisioningUri"`
+	Secret          string `json:"secret"`
+	// One-time codes to use when the authenticator device is lost, only shown once
+	RecoveryCodes []string `json:"recoveryCodes"`
 }
 
+// Omitted fields are left unchanged, dob is formatted YYYY-MM-DD
 type UpdateEmployeeInput struct {
-	FirstName    string `json:"firstName"`
-	LastName     string `json:"lastName"`
-	UserName     string `json:"userName"`
-	Password     string `json:"password"`
-	Email        string `json:"email"`
-	Dob          string `json:"dob"`
-	DepartmentID string `json:"departmentID"`
-	Position     string `json:"position"`
+	FirstName    *string `json:"firstName,omitempty"`
+	LastName     *string `json:"lastName,omitempty"`
+	Dob          *string `json:"dob,omitempty"`
+	DepartmentID *string `json:"departmentID,omitempty"`
+	Position     *string `json:"position,omitempty"`
 }
 
 type User struct {
 	ID        string  `json:"id"`
 	UserName  string  `json:"userName"`
-	Password  string  `json:"password"`
 	CreatedAt *string `json:"createdAt,omitempty"`
 	UpdatedAt *string `json:"updatedAt,omitempty"`
 }
@@ -60,3 +131,134 @@ type UserRequest struct {
 	UserName string `json:"userName"`
 	Password string `json:"password"`
 }
+
+type DocumentKind string
+
+const (
+	DocumentKindContract DocumentKind = "CONTRACT"
+	DocumentKindIdentity DocumentKind = "IDENTITY"
+	DocumentKindOther    DocumentKind = "OTHER"
+)
+
+var AllDocumentKind = []DocumentKind{
+	DocumentKindContract,
+	DocumentKindIdentity,
+	DocumentKindOther,
+}
+
+func (e DocumentKind) IsValid() bool {
+	switch e {
+	case DocumentKindContract, DocumentKindIdentity, DocumentKindOther:
+		return true
+	}
+	return false
+}
+
+func (e DocumentKind) String() string {
+	return string(e)
+}
+
+func (e *DocumentKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DocumentKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DocumentKind", str)
+	}
+	return nil
+}
+
+func (e DocumentKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// The square thumbnails a profile photo is stored as
+type PhotoSize string
+
+const (
+	// 64 by 64 pixels
+	PhotoSizeSmall PhotoSize = "SMALL"
+	// 256 by 256 pixels
+	PhotoSizeMedium PhotoSize = "MEDIUM"
+	// 512 by 512 pixels
+	PhotoSizeLarge PhotoSize = "LARGE"
+)
+
+var AllPhotoSize = []PhotoSize{
+	PhotoSizeSmall,
+	PhotoSizeMedium,
+	PhotoSizeLarge,
+}
+
+func (e PhotoSize) IsValid() bool {
+	switch e {
+	case PhotoSizeSmall, PhotoSizeMedium, PhotoSizeLarge:
+		return true
+	}
+	return false
+}
+
+func (e PhotoSize) String() string {
+	return string(e)
+}
+
+func (e *PhotoSize) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PhotoSize(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PhotoSize", str)
+	}
+	return nil
+}
+
+func (e PhotoSize) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Role string
+
+const (
+	RoleAdministrator Role = "ADMINISTRATOR"
+	RoleStaff         Role = "STAFF"
+)
+
+var AllRole = []Role{
+	RoleAdministrator,
+	RoleStaff,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleAdministrator, RoleStaff:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,11 @@
+package model
+
+// Owned is an object belonging to a user, whose @sensitive fields that user may always see
+type Owned interface {
+	OwnerID() string
+}
+
+// OwnerID returns the id of the user the employee record belongs to
+func (e *Employee) OwnerID() string {
+	return e.UserID
+}
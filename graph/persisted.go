@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errPersistedQueryNotAllowedCode = "PERSISTED_QUERY_NOT_ALLOWED"
+)
+
+// PersistedQueries only executes queries from an allow list, for production where clients ship
+// their queries at build time. Clients send the sha256 hash of the query as the Apollo
+// persistedQuery extension, with or without the query text.
+type PersistedQueries struct {
+	queries map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = PersistedQueries{}
+
+// LoadPersistedQueries reads the allow list, a JSON object mapping sha256 hashes to query text
+func LoadPersistedQueries(path string) (PersistedQueries, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PersistedQueries{}, err
+	}
+
+	var queries map[string]string
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return PersistedQueries{}, fmt.Errorf("persisted queries %s: %w", path, err)
+	}
+	for hash, query := range queries {
+		if queryHash(query) != hash {
+			return PersistedQueries{}, fmt.Errorf("persisted queries %s: hash %s does not match its query", path, hash)
+		}
+	}
+	return PersistedQueries{queries: queries}, nil
+}
+
+// ExtensionName of the gqlgen extension
+func (PersistedQueries) ExtensionName() string {
+	return "PersistedQueries"
+}
+
+// Validate the extension against the schema, there is nothing to check
+func (PersistedQueries) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters replaces the request with the allow-listed query of its hash, rejecting
+// anything else
+func (p PersistedQueries) MutateOperationParameters(_ context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := queryHash(rawParams.Query)
+	if persisted, ok := rawParams.Extensions["persistedQuery"].(map[string]interface{}); ok {
+		if h, ok := persisted["sha256Hash"].(string); ok && (rawParams.Query == "" || h == hash) {
+			hash = h
+		}
+	}
+
+	query, ok := p.queries[hash]
+	if !ok {
+		err := gqlerror.Errorf("query is not in the persisted query allow list")
+		errcode.Set(err, errPersistedQueryNotAllowedCode)
+		return err
+	}
+	rawParams.Query = query
+	return nil
+}
+
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,6 +1,10 @@
 package graph
 
-import "database/sql"
+import (
+	"database/sql"
+
+	controller "employee-management-system/controllers"
+)
 
 //go:generate go run github.com/99designs/gqlgen generate
 
@@ -9,12 +13,14 @@ import "database/sql"
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	db *sql.DB
+	db         *sql.DB
+	controller controller.Operations
 }
 
 // New created a new instance of Resolver
-func New(db *sql.DB) *Resolver {
+func New(db *sql.DB, c controller.Operations) *Resolver {
 	return &Resolver{
-		db: db,
+		db:         db,
+		controller: c,
 	}
 }
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	controller "employee-management-system/controllers"
+	appModel "employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+var henry = appModel.Employee{ID: 1, FirstName: "Henry", LastName: "Erabor", Email: "henry@yahoo.com", Position: "Software Engineer"}
+
+// fakeOperations serves employees from memory, without authorization
+type fakeOperations struct {
+	controller.Operations
+	employees map[int]appModel.Employee
+}
+
+func (f *fakeOperations) GetEmployeeByID(_ context.Context, id int) (appModel.Employee, error) {
+	employee, ok := f.employees[id]
+	if !ok {
+		return appModel.Employee{}, storage.ErrRecordNotFound
+	}
+	return employee, nil
+}
+
+func (f *fakeOperations) GetAllEmployees(context.Context) ([]*appModel.Employee, error) {
+	var employees []*appModel.Employee
+	for _, employee := range f.employees {
+		employee := employee
+		employees = append(employees, &employee)
+	}
+	return employees, nil
+}
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// query posts query to h as user, signed out when user is nil
+func query(t *testing.T, h http.Handler, user *appModel.User, query string) response {
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if user != nil {
+		req = req.WithContext(middleware.ContextWithUser(req.Context(), user))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var resp response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return resp
+}
+
+func (r response) codes() []string {
+	codes := []string{}
+	for _, e := range r.Errors {
+		code, _ := e.Extensions["code"].(string)
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func Test_EmployeeResolvers(t *testing.T) {
+	h := newTestServer(t, config.Default().GraphQL)
+	admin := &appModel.User{ID: 1, Kind: appModel.KindAdministrator}
+
+	resp := query(t, h, admin, `{ getEmployee(id: "1") { id firstName } }`)
+	require.Empty(t, resp.Errors)
+	require.Equal(t, map[string]interface{}{"id": "1", "firstName": "Henry"}, resp.Data["getEmployee"])
+
+	resp = query(t, h, admin, `{ getAllEmployees { id } }`)
+	require.Equal(t, []interface{}{map[string]interface{}{"id": "1"}}, resp.Data["getAllEmployees"])
+
+	require.Equal(t, []string{apierror.CodeNotFound}, query(t, h, admin, `{ getEmployee(id: "2") { id } }`).codes())
+	require.Equal(t, []string{apierror.CodeBadUserInput}, query(t, h, admin, `{ getEmployee(id: "one") { id } }`).codes())
+}
+
+func Test_EmployeeMutations_Staff(t *testing.T) {
+	// the real controller, its authorization rejects staff before any query is made
+	mock, s := storage.GetStorage(t)
+	cfg := config.Default()
+	s.Config = &cfg
+	srv, err := NewServer(NewExecutableSchema(Config{
+		Resolvers:  New(nil, *controller.New(zerolog.Nop(), s, nil)),
+		Complexity: Complexity(cfg.GraphQL.ListMultiplier),
+		Directives: Directives(),
+	}), cfg.GraphQL, 1<<20)
+	require.NoError(t, err)
+	staff := &appModel.User{ID: 7, Kind: appModel.KindStaff}
+
+	for _, mutation := range []string{
+		`mutation { createEmployee(input: {userID: "7", firstName: "Jane", lastName: "Doe", email: "jane@company.com", dob: "1990-04-01"}) { id } }`,
+		`mutation { deleteEmployee(id: "1") { deleteEmployeeId } }`,
+	} {
+		require.Equal(t, []string{apierror.CodeForbidden}, query(t, srv, staff, mutation).codes(), mutation)
+	}
+	require.Equal(t, []string{apierror.CodeBadUserInput}, query(t, srv, staff, `mutation { updateEmployee(id: "1", input: {dob: "April"}) { id } }`).codes())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -7,73 +7,339 @@ package graph
 import (
 	"context"
 	"employee-management-system/graph/model"
-	"fmt"
+	"employee-management-system/pkg/middleware"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
+// Download is the resolver for the download field.
+func (r *documentResolver) Download(ctx context.Context, obj *model.Document) (*model.DocumentDownload, error) {
+	id, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, expiresAt, err := r.controller.DocumentURL(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DocumentDownload{URL: url, ExpiresAt: expiresAt.Format(time.RFC3339)}, nil
+}
+
+// PhotoURL is the resolver for the photoUrl field.
+func (r *employeeResolver) PhotoURL(ctx context.Context, obj *model.Employee, size model.PhotoSize) (*string, error) {
+	id, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := r.controller.ProfilePhotoURL(ctx, id, fromPhotoSize(size))
+	if err != nil || url == "" {
+		return nil, err
+	}
+
+	return &url, nil
+}
+
 // CreateEmployee is the resolver for the createEmployee field.
 func (r *mutationResolver) CreateEmployee(ctx context.Context, input model.CreateEmployeeInput) (*model.Employee, error) {
-	employee := &model.Employee{
-		FirstName: input.FirstName,
-		LastName:  input.LastName,
-		Password:  input.Password,
-		Email:     input.DepartmentID,
-		Dob:       input.Dob,
-		Position:  input.Position,
+	employee, err := fromCreateEmployeeInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err = r.controller.AddEmployee(ctx, employee)
+	if err != nil {
+		return nil, err
 	}
 
-	return employee, nil
+	return toEmployee(employee), nil
 }
 
 // UpdateEmployee is the resolver for the updateEmployee field.
 func (r *mutationResolver) UpdateEmployee(ctx context.Context, id string, input model.UpdateEmployeeInput) (*model.Employee, error) {
-	panic(fmt.Errorf("not implemented: UpdateEmployee - updateEmployee"))
+	employeeID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := fromUpdateEmployeeInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.controller.UpdateEmployeeByID(ctx, employeeID, changes); err != nil {
+		return nil, err
+	}
+	// read again so staff get the view they may see
+	employee, err := r.controller.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEmployee(employee), nil
 }
 
 // DeleteEmployee is the resolver for the deleteEmployee field.
 func (r *mutationResolver) DeleteEmployee(ctx context.Context, id string) (*model.DeleteEmployeeResponse, error) {
-	panic(fmt.Errorf("not implemented: DeleteEmployee - deleteEmployee"))
+	employeeID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.controller.DeleteEmployeeByID(ctx, employeeID); err != nil {
+		return nil, err
+	}
+
+	return &model.DeleteEmployeeResponse{DeleteEmployeeID: id}, nil
+}
+
+// CreateAPIKey is the resolver for the createAPIKey field.
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, input model.CreateAPIKeyInput) (*model.CreateAPIKeyResponse, error) {
+	expiresAt, err := parseTime(input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	key, secret, err := r.controller.CreateAPIKey(ctx, input.ServiceAccount, fromRole(input.Role), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CreateAPIKeyResponse{
+		APIKey: toAPIKey(key),
+		Key:    secret.String(),
+	}, nil
+}
+
+// RevokeAPIKey is the resolver for the revokeAPIKey field.
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, id string) (*model.APIKey, error) {
+	keyID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.controller.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAPIKey(key), nil
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, input model.UserRequest) (*model.AuthResponse, error) {
+	gc, err := middleware.GinContextFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.controller.Middleware().JwtAuthenticator(gc, input.UserName, input.Password)
+}
+
+// EnrollTwoFactor is the resolver for the enrollTwoFactor field.
+func (r *mutationResolver) EnrollTwoFactor(ctx context.Context, challengeToken *string) (*model.TwoFactorEnrollment, error) {
+	gc, err := middleware.GinContextFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.controller.Middleware().EnrollTwoFactor(gc, challengeToken)
+}
+
+// VerifyTwoFactor is the resolver for the verifyTwoFactor field.
+func (r *mutationResolver) VerifyTwoFactor(ctx context.Context, challengeToken string, code string) (*model.AuthResponse, error) {
+	gc, err := middleware.GinContextFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.controller.Middleware().VerifyTwoFactor(gc, challengeToken, code)
+}
+
+// DisableTwoFactor is the resolver for the disableTwoFactor field.
+func (r *mutationResolver) DisableTwoFactor(ctx context.Context, code string) (bool, error) {
+	gc, err := middleware.GinContextFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.controller.Middleware().DisableTwoFactor(gc, code); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
+	sessionID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.controller.RevokeSession(ctx, sessionID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RevokeAllSessions is the resolver for the revokeAllSessions field.
+func (r *mutationResolver) RevokeAllSessions(ctx context.Context, userID string) (int, error) {
+	uID, err := strconv.Atoi(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := r.controller.RevokeAllSessions(ctx, uID)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// UploadDocument is the resolver for the uploadDocument field.
+func (r *mutationResolver) UploadDocument(ctx context.Context, employeeID string, kind model.DocumentKind, file graphql.Upload) (*model.Document, error) {
+	id, err := strconv.Atoi(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := r.controller.UploadDocument(ctx, id, fromDocumentKind(kind), file.Filename, file.File, file.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDocument(document), nil
+}
+
+// DeleteDocument is the resolver for the deleteDocument field.
+func (r *mutationResolver) DeleteDocument(ctx context.Context, id string) (bool, error) {
+	documentID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.controller.DeleteDocument(ctx, documentID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UploadProfilePhoto is the resolver for the uploadProfilePhoto field.
+func (r *mutationResolver) UploadProfilePhoto(ctx context.Context, employeeID string, file graphql.Upload) (*model.Employee, error) {
+	id, err := strconv.Atoi(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := r.controller.UploadProfilePhoto(ctx, id, file.File, file.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEmployee(employee), nil
 }
 
 // GetAllEmployees is the resolver for the getAllEmployees field.
 func (r *queryResolver) GetAllEmployees(ctx context.Context) ([]*model.Employee, error) {
-	employees := []*model.Employee{
-		{
-			ID:        "1",
-			FirstName: "Henry",
-			LastName:  "Erabor",
-			Email:     "henry@yahoo.com",
-			Position:  "Software Engineer",
-		},
-		{
-			ID:        "2",
-			FirstName: "James",
-			LastName:  "Erabor",
-			Email:     "james@yahoo.com",
-			Position:  "Mechanical Engineer ",
-		},
-	}
-
-	return employees, nil
+	employees, err := r.controller.GetAllEmployees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]*model.Employee, 0, len(employees))
+	for _, employee := range employees {
+		all = append(all, toEmployee(*employee))
+	}
+
+	return all, nil
 }
 
 // GetEmployee is the resolver for the getEmployee field.
 func (r *queryResolver) GetEmployee(ctx context.Context, id string) (*model.Employee, error) {
-	employee := &model.Employee{
-		ID:        "1",
-		FirstName: "Henry",
-		LastName:  "Erabor",
-		Email:     "henry@yahoo.com",
-		Position:  "Software Engineer",
+	employeeID, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := r.controller.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEmployee(employee), nil
+}
+
+// APIKeys is the resolver for the apiKeys field.
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	keys, err := r.controller.GetAllAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys := make([]*model.APIKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, toAPIKey(*key))
+	}
+
+	return apiKeys, nil
+}
+
+// MySessions is the resolver for the mySessions field.
+func (r *queryResolver) MySessions(ctx context.Context) ([]*model.Session, error) {
+	sessions, err := r.controller.GetMySessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := middleware.SessionIDFromContext(ctx)
+	mySessions := make([]*model.Session, 0, len(sessions))
+	for _, session := range sessions {
+		mySessions = append(mySessions, toSession(*session, current))
+	}
+
+	return mySessions, nil
+}
+
+// Documents is the resolver for the documents field.
+func (r *queryResolver) Documents(ctx context.Context, employeeID string) ([]*model.Document, error) {
+	id, err := strconv.Atoi(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := r.controller.GetDocuments(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	return employee, nil
+	employeeDocuments := make([]*model.Document, 0, len(documents))
+	for _, document := range documents {
+		employeeDocuments = append(employeeDocuments, toDocument(*document))
+	}
+
+	return employeeDocuments, nil
 }
 
+// Document returns DocumentResolver implementation.
+func (r *Resolver) Document() DocumentResolver { return &documentResolver{r} }
+
+// Employee returns EmployeeResolver implementation.
+func (r *Resolver) Employee() EmployeeResolver { return &employeeResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+type documentResolver struct{ *Resolver }
+type employeeResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
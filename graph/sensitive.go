@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"employee-management-system/graph/model"
+	"employee-management-system/pkg/middleware"
+)
+
+// Directives returns the implementation of the schema directives
+func Directives() DirectiveRoot {
+	return DirectiveRoot{
+		Sensitive: Sensitive,
+	}
+}
+
+// Sensitive resolves a @sensitive field only for the user owning obj or a caller holding one of
+// roles, anyone else gets null
+func Sensitive(ctx context.Context, obj interface{}, next graphql.Resolver, roles []model.Role) (interface{}, error) {
+	user, err := middleware.UserFromContext(ctx)
+	if err != nil {
+		return nil, nil
+	}
+	for _, role := range roles {
+		if toRole(user.Kind) == role {
+			return next(ctx)
+		}
+	}
+	if owned, ok := obj.(model.Owned); ok && owned.OwnerID() == strconv.Itoa(user.ID) {
+		return next(ctx)
+	}
+	return nil, nil
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/graph/model"
+	appModel "employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/middleware"
+)
+
+func Test_Sensitive(t *testing.T) {
+	employee := &model.Employee{ID: "10", UserID: "2"}
+	next := func(context.Context) (interface{}, error) { return "1990-04-01", nil }
+	resolve := func(user *appModel.User, roles ...model.Role) interface{} {
+		ctx := context.Background()
+		if user != nil {
+			ctx = middleware.ContextWithUser(ctx, user)
+		}
+		value, err := Sensitive(ctx, employee, next, roles)
+		require.NoError(t, err)
+		return value
+	}
+
+	admin := &appModel.User{ID: 1, Kind: appModel.KindAdministrator}
+	owner := &appModel.User{ID: 2, Kind: appModel.KindStaff}
+	other := &appModel.User{ID: 3, Kind: appModel.KindStaff}
+
+	require.Equal(t, "1990-04-01", resolve(admin, model.RoleAdministrator))
+	require.Equal(t, "1990-04-01", resolve(owner, model.RoleAdministrator))
+	require.Nil(t, resolve(other, model.RoleAdministrator))
+	require.Equal(t, "1990-04-01", resolve(other, model.RoleAdministrator, model.RoleStaff))
+	require.Nil(t, resolve(nil, model.RoleAdministrator))
+}
+
+func Test_SensitiveEmail(t *testing.T) {
+	h := newTestServer(t, config.Default().GraphQL)
+	email := func(user *appModel.User) interface{} {
+		employee, _ := query(t, h, user, `{ getEmployee(id: "1") { email } }`).Data["getEmployee"].(map[string]interface{})
+		return employee["email"]
+	}
+
+	// the record belongs to no user, only administrators see the address
+	require.Equal(t, "henry@yahoo.com", email(&appModel.User{ID: 1, Kind: appModel.KindAdministrator}))
+	require.Nil(t, email(&appModel.User{ID: 3, Kind: appModel.KindStaff}))
+	require.Nil(t, email(nil))
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+
+	"employee-management-system/pkg/config"
+)
+
+// multipartOverhead is allowed on top of the largest file for the operations and map parts of an
+// upload request
+const multipartOverhead = 1 << 20
+
+// NewServer builds the GraphQL handler like handler.NewDefaultServer, with the cost limits and the
+// automatic persisted query cache, or the persisted query allow list, from cfg. Upload requests
+// carry a file of at most maxUploadSize bytes.
+func NewServer(es graphql.ExecutableSchema, cfg config.GraphQL, maxUploadSize int64) (*handler.Server, error) {
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{MaxUploadSize: maxUploadSize + multipartOverhead})
+
+	srv.SetQueryCache(lru.New(1000))
+	srv.SetErrorPresenter(errorPresenter)
+
+	srv.Use(extension.Introspection{})
+	if cfg.PersistedQueriesOnly {
+		allowList, err := LoadPersistedQueries(cfg.PersistedQueriesFile)
+		if err != nil {
+			return nil, err
+		}
+		srv.Use(allowList)
+	} else {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New(cfg.APQCacheSize),
+		})
+	}
+	srv.Use(extension.FixedComplexityLimit(cfg.ComplexityLimit))
+	srv.Use(DepthLimit{Limit: cfg.DepthLimit})
+
+	return srv, nil
+}
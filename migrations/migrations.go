@@ -0,0 +1,10 @@
+// Package migrations embeds the goose SQL migrations, so every binary carries the schema it expects
+package migrations
+
+import "embed"
+
+// FS holds one directory of migrations per database driver, named after the driver. The
+// directories hold the same versions, each written in the SQL of its database.
+//
+//go:embed sqlserver/*.sql postgres/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    user_name VARCHAR(100) NOT NULL CONSTRAINT uq_users_user_name UNIQUE,
+    -- bcrypt hash, empty for service accounts
+    password VARCHAR(100) NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    deleted_at TIMESTAMPTZ NULL
+);
+
+-- +goose Down
+DROP TABLE users;
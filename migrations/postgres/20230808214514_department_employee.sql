@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE departments (
+    id SERIAL PRIMARY KEY,
+    department_name VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMPTZ NULL,
+    deleted_at TIMESTAMPTZ NULL
+);
+
+-- departmentID is quoted, the models name it in mixed case
+CREATE TABLE employees (
+    id SERIAL PRIMARY KEY,
+    user_id INT NULL REFERENCES users(id),
+    first_name VARCHAR(50) NOT NULL,
+    last_name VARCHAR(50) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    dob DATE NULL,
+    "departmentID" INT NULL REFERENCES departments(id),
+    position VARCHAR(50) NULL,
+    updated_at TIMESTAMPTZ NULL,
+    deleted_at TIMESTAMPTZ NULL
+);
+CREATE INDEX ix_employees_user_id ON employees (user_id);
+CREATE INDEX ix_employees_department_id ON employees ("departmentID");
+
+-- +goose Down
+DROP TABLE employees;
+DROP TABLE departments;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN kind INT NOT NULL DEFAULT 2,
+    ADD COLUMN service_account BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    prefix VARCHAR(32) NOT NULL,
+    key_hash CHAR(64) NOT NULL CONSTRAINT uq_api_keys_key_hash UNIQUE,
+    created_by INT NOT NULL,
+    expires_at TIMESTAMPTZ NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE api_keys;
+ALTER TABLE users
+    DROP COLUMN kind,
+    DROP COLUMN service_account;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    user_agent VARCHAR(512) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NULL
+);
+CREATE INDEX ix_sessions_user_id ON sessions (user_id, revoked_at, expires_at);
+
+-- +goose Down
+DROP TABLE sessions;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN locked_at TIMESTAMPTZ NULL;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN locked_at;
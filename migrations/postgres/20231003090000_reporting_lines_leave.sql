@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE employees ADD COLUMN manager_id INT NULL CONSTRAINT fk_employees_manager REFERENCES employees(id);
+CREATE INDEX ix_employees_manager_id ON employees (manager_id);
+
+CREATE TABLE leave_records (
+    id SERIAL PRIMARY KEY,
+    employee_id INT NOT NULL REFERENCES employees(id),
+    kind VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX ix_leave_records_employee_id ON leave_records (employee_id, starts_on);
+
+-- +goose Down
+DROP TABLE leave_records;
+ALTER TABLE employees DROP COLUMN manager_id;
@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE tenants (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL CONSTRAINT uq_tenants_name UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+-- existing rows belong to the default tenant, id 1
+INSERT INTO tenants (name, created_at, updated_at) VALUES ('Default', NOW(), NOW());
+
+ALTER TABLE users ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE departments ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE employees ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE leave_records ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE api_keys ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+
+CREATE INDEX ix_users_tenant_id ON users (tenant_id);
+CREATE INDEX ix_departments_tenant_id ON departments (tenant_id);
+CREATE INDEX ix_employees_tenant_id ON employees (tenant_id);
+CREATE INDEX ix_leave_records_tenant_id ON leave_records (tenant_id);
+CREATE INDEX ix_api_keys_tenant_id ON api_keys (tenant_id);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN tenant_id;
+ALTER TABLE departments DROP COLUMN tenant_id;
+ALTER TABLE employees DROP COLUMN tenant_id;
+ALTER TABLE leave_records DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+DROP TABLE tenants;
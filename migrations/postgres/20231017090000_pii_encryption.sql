@@ -0,0 +1,14 @@
+-- +goose Up
+-- encrypted values are longer than the plaintext and dates are stored as text, existing rows are
+-- encrypted by `admin pii reencrypt`
+ALTER TABLE employees ALTER COLUMN email TYPE VARCHAR(512);
+ALTER TABLE employees ALTER COLUMN dob TYPE VARCHAR(512) USING dob::text;
+ALTER TABLE employees ADD COLUMN email_index CHAR(64) NULL;
+CREATE INDEX ix_employees_email_index ON employees (tenant_id, email_index);
+
+-- +goose Down
+DROP INDEX ix_employees_email_index;
+ALTER TABLE employees DROP COLUMN email_index;
+-- only unencrypted values convert back
+ALTER TABLE employees ALTER COLUMN dob TYPE DATE USING dob::date;
+ALTER TABLE employees ALTER COLUMN email TYPE VARCHAR(100);
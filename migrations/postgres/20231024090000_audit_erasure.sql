@@ -0,0 +1,20 @@
+-- +goose Up
+-- audit entries outlive the users and employees they are about, the subject columns have no foreign key
+CREATE TABLE audit_entries (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL REFERENCES tenants(id),
+    actor_id INT NULL,
+    action VARCHAR(64) NOT NULL,
+    subject_user_id INT NULL,
+    subject_employee_id INT NULL,
+    detail VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX ix_audit_entries_subject_user_id ON audit_entries (tenant_id, subject_user_id);
+CREATE INDEX ix_audit_entries_subject_employee_id ON audit_entries (tenant_id, subject_employee_id);
+
+ALTER TABLE employees ADD COLUMN erased_at TIMESTAMPTZ NULL;
+
+-- +goose Down
+ALTER TABLE employees DROP COLUMN erased_at;
+DROP TABLE audit_entries;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE documents (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL REFERENCES tenants(id),
+    employee_id INT NOT NULL REFERENCES employees(id),
+    kind VARCHAR(32) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL CONSTRAINT uq_documents_storage_key UNIQUE,
+    uploaded_by INT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX ix_documents_employee_id ON documents (tenant_id, employee_id);
+
+-- +goose Down
+DROP TABLE documents;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE employees ADD COLUMN photo_key VARCHAR(255) NULL;
+
+-- +goose Down
+ALTER TABLE employees DROP COLUMN photo_key;
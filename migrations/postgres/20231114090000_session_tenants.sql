@@ -0,0 +1,13 @@
+-- +goose Up
+-- sessions and recovery codes belong to the tenant of their user
+ALTER TABLE sessions ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE recovery_codes ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id);
+UPDATE sessions SET tenant_id = users.tenant_id FROM users WHERE users.id = sessions.user_id;
+UPDATE recovery_codes SET tenant_id = users.tenant_id FROM users WHERE users.id = recovery_codes.user_id;
+
+CREATE INDEX ix_sessions_tenant_id ON sessions (tenant_id, user_id);
+CREATE INDEX ix_recovery_codes_tenant_id ON recovery_codes (tenant_id, user_id);
+
+-- +goose Down
+ALTER TABLE sessions DROP COLUMN tenant_id;
+ALTER TABLE recovery_codes DROP COLUMN tenant_id;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_name TEXT NOT NULL CONSTRAINT uq_users_user_name UNIQUE,
+    -- bcrypt hash, empty for service accounts
+    password TEXT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    deleted_at DATETIME NULL
+);
+
+-- +goose Down
+DROP TABLE users;
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE departments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    department_name TEXT NOT NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE employees (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NULL REFERENCES users(id),
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    dob DATE NULL,
+    departmentID INTEGER NULL REFERENCES departments(id),
+    position TEXT NULL,
+    updated_at DATETIME NULL,
+    deleted_at DATETIME NULL
+);
+CREATE INDEX ix_employees_user_id ON employees (user_id);
+CREATE INDEX ix_employees_department_id ON employees (departmentID);
+
+-- +goose Down
+DROP TABLE employees;
+DROP TABLE departments;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- SQLite adds and drops one column per statement
+ALTER TABLE users ADD COLUMN kind INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE users ADD COLUMN service_account BOOLEAN NOT NULL DEFAULT 0;
+
+CREATE TABLE api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL CONSTRAINT uq_api_keys_key_hash UNIQUE,
+    created_by INTEGER NOT NULL,
+    expires_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE api_keys;
+ALTER TABLE users DROP COLUMN kind;
+ALTER TABLE users DROP COLUMN service_account;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    user_agent TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL
+);
+CREATE INDEX ix_sessions_user_id ON sessions (user_id, revoked_at, expires_at);
+
+-- +goose Down
+DROP TABLE sessions;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- without REFERENCES, SQLite cannot drop a column that is part of a foreign key
+ALTER TABLE employees ADD COLUMN manager_id INTEGER NULL;
+CREATE INDEX ix_employees_manager_id ON employees (manager_id);
+
+CREATE TABLE leave_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX ix_leave_records_employee_id ON leave_records (employee_id, starts_on);
+
+-- +goose Down
+DROP TABLE leave_records;
+DROP INDEX ix_employees_manager_id;
+ALTER TABLE employees DROP COLUMN manager_id;
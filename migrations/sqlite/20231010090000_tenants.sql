@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE tenants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL CONSTRAINT uq_tenants_name UNIQUE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+-- existing rows belong to the default tenant, id 1
+INSERT INTO tenants (name, created_at, updated_at) VALUES ('Default', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- SQLite only adds a REFERENCES column whose default is NULL, the tenant columns go without
+ALTER TABLE users ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE departments ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE employees ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE leave_records ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE api_keys ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX ix_users_tenant_id ON users (tenant_id);
+CREATE INDEX ix_departments_tenant_id ON departments (tenant_id);
+CREATE INDEX ix_employees_tenant_id ON employees (tenant_id);
+CREATE INDEX ix_leave_records_tenant_id ON leave_records (tenant_id);
+CREATE INDEX ix_api_keys_tenant_id ON api_keys (tenant_id);
+
+-- +goose Down
+DROP INDEX ix_users_tenant_id;
+DROP INDEX ix_departments_tenant_id;
+DROP INDEX ix_employees_tenant_id;
+DROP INDEX ix_leave_records_tenant_id;
+DROP INDEX ix_api_keys_tenant_id;
+ALTER TABLE users DROP COLUMN tenant_id;
+ALTER TABLE departments DROP COLUMN tenant_id;
+ALTER TABLE employees DROP COLUMN tenant_id;
+ALTER TABLE leave_records DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+DROP TABLE tenants;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- SQLite cannot change the type of a column, dob is copied to a TEXT column so the driver no longer
+-- reads it as a date. Existing rows are encrypted by `admin pii reencrypt`.
+ALTER TABLE employees ADD COLUMN dob_text TEXT NULL;
+UPDATE employees SET dob_text = dob;
+ALTER TABLE employees DROP COLUMN dob;
+ALTER TABLE employees RENAME COLUMN dob_text TO dob;
+ALTER TABLE employees ADD COLUMN email_index TEXT NULL;
+CREATE INDEX ix_employees_email_index ON employees (tenant_id, email_index);
+
+-- +goose Down
+DROP INDEX ix_employees_email_index;
+ALTER TABLE employees DROP COLUMN email_index;
+-- only unencrypted values convert back
+ALTER TABLE employees ADD COLUMN dob_date DATE NULL;
+UPDATE employees SET dob_date = dob;
+ALTER TABLE employees DROP COLUMN dob;
+ALTER TABLE employees RENAME COLUMN dob_date TO dob;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- audit entries outlive the users and employees they are about, the subject columns have no foreign key
+CREATE TABLE audit_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+    actor_id INTEGER NULL,
+    action TEXT NOT NULL,
+    subject_user_id INTEGER NULL,
+    subject_employee_id INTEGER NULL,
+    detail TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX ix_audit_entries_subject_user_id ON audit_entries (tenant_id, subject_user_id);
+CREATE INDEX ix_audit_entries_subject_employee_id ON audit_entries (tenant_id, subject_employee_id);
+
+ALTER TABLE employees ADD COLUMN erased_at DATETIME NULL;
+
+-- +goose Down
+ALTER TABLE employees DROP COLUMN erased_at;
+DROP INDEX ix_audit_entries_subject_user_id;
+DROP INDEX ix_audit_entries_subject_employee_id;
+DROP TABLE audit_entries;
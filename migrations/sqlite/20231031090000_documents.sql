@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE documents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id),
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    kind TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    checksum TEXT NOT NULL,
+    storage_key TEXT NOT NULL CONSTRAINT uq_documents_storage_key UNIQUE,
+    uploaded_by INTEGER NULL REFERENCES users(id),
+    created_at DATETIME NOT NULL
+);
+CREATE INDEX ix_documents_employee_id ON documents (tenant_id, employee_id);
+
+-- +goose Down
+DROP INDEX ix_documents_employee_id;
+DROP TABLE documents;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- sessions and recovery codes belong to the tenant of their user
+ALTER TABLE sessions ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE recovery_codes ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+UPDATE sessions SET tenant_id = (SELECT tenant_id FROM users WHERE users.id = sessions.user_id);
+UPDATE recovery_codes SET tenant_id = (SELECT tenant_id FROM users WHERE users.id = recovery_codes.user_id);
+
+CREATE INDEX ix_sessions_tenant_id ON sessions (tenant_id, user_id);
+CREATE INDEX ix_recovery_codes_tenant_id ON recovery_codes (tenant_id, user_id);
+
+-- +goose Down
+DROP INDEX ix_sessions_tenant_id;
+DROP INDEX ix_recovery_codes_tenant_id;
+ALTER TABLE sessions DROP COLUMN tenant_id;
+ALTER TABLE recovery_codes DROP COLUMN tenant_id;
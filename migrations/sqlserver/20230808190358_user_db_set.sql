@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    user_name NVARCHAR(100) NOT NULL CONSTRAINT uq_users_user_name UNIQUE,
+    -- bcrypt hash, empty for service accounts
+    password NVARCHAR(100) NULL,
+    created_at DATETIMEOFFSET NOT NULL,
+    updated_at DATETIMEOFFSET NOT NULL,
+    deleted_at DATETIMEOFFSET NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE users;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE departments (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    department_name NVARCHAR(50) NOT NULL,
+    updated_at DATETIMEOFFSET NULL,
+    deleted_at DATETIMEOFFSET NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE employees (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    user_id INT NULL REFERENCES users(id),
+    first_name NVARCHAR(50) NOT NULL,
+    last_name NVARCHAR(50) NOT NULL,
+    email NVARCHAR(100) NOT NULL,
+    dob DATE NULL,
+    departmentID INT NULL REFERENCES departments(id),
+    position NVARCHAR(50) NULL,
+    updated_at DATETIMEOFFSET NULL,
+    deleted_at DATETIMEOFFSET NULL
+);
+CREATE INDEX ix_employees_user_id ON employees (user_id);
+CREATE INDEX ix_employees_department_id ON employees (departmentID);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE employees;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE departments;
+-- +goose StatementEnd
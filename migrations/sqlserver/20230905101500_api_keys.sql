@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD
+    kind INT NOT NULL CONSTRAINT df_users_kind DEFAULT 2,
+    service_account BIT NOT NULL CONSTRAINT df_users_service_account DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE api_keys (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    user_id INT NOT NULL REFERENCES users(id),
+    prefix NVARCHAR(32) NOT NULL,
+    key_hash CHAR(64) NOT NULL CONSTRAINT uq_api_keys_key_hash UNIQUE,
+    created_by INT NOT NULL,
+    expires_at DATETIMEOFFSET NULL,
+    last_used_at DATETIMEOFFSET NULL,
+    revoked_at DATETIMEOFFSET NULL,
+    created_at DATETIMEOFFSET NOT NULL,
+    updated_at DATETIMEOFFSET NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_keys;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT df_users_kind, df_users_service_account;
+ALTER TABLE users DROP COLUMN kind, service_account;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD
+    totp_secret NVARCHAR(64) NULL,
+    totp_enabled_at DATETIMEOFFSET NULL,
+    totp_last_step BIGINT NOT NULL CONSTRAINT df_users_totp_last_step DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE recovery_codes (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    user_id INT NOT NULL REFERENCES users(id),
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIMEOFFSET NULL,
+    created_at DATETIMEOFFSET NOT NULL
+);
+CREATE INDEX ix_recovery_codes_user_id ON recovery_codes (user_id, code_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE recovery_codes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT df_users_totp_last_step;
+ALTER TABLE users DROP COLUMN totp_secret, totp_enabled_at, totp_last_step;
+-- +goose StatementEnd
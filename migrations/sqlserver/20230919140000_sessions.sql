@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    user_id INT NOT NULL REFERENCES users(id),
+    user_agent NVARCHAR(512) NOT NULL,
+    ip_address NVARCHAR(64) NOT NULL,
+    created_at DATETIMEOFFSET NOT NULL,
+    last_seen_at DATETIMEOFFSET NOT NULL,
+    expires_at DATETIMEOFFSET NOT NULL,
+    revoked_at DATETIMEOFFSET NULL
+);
+CREATE INDEX ix_sessions_user_id ON sessions (user_id, revoked_at, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE sessions;
+-- +goose StatementEnd
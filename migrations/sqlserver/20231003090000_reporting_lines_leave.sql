@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE employees ADD manager_id INT NULL CONSTRAINT fk_employees_manager REFERENCES employees(id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX ix_employees_manager_id ON employees (manager_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE leave_records (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    employee_id INT NOT NULL REFERENCES employees(id),
+    kind NVARCHAR(20) NOT NULL,
+    status NVARCHAR(20) NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    created_at DATETIMEOFFSET NOT NULL
+);
+CREATE INDEX ix_leave_records_employee_id ON leave_records (employee_id, starts_on);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE leave_records;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX ix_employees_manager_id ON employees;
+ALTER TABLE employees DROP CONSTRAINT fk_employees_manager;
+ALTER TABLE employees DROP COLUMN manager_id;
+-- +goose StatementEnd
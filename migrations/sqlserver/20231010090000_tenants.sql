@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE tenants (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    name NVARCHAR(100) NOT NULL CONSTRAINT uq_tenants_name UNIQUE,
+    created_at DATETIMEOFFSET NOT NULL,
+    updated_at DATETIMEOFFSET NOT NULL
+);
+-- existing rows belong to the default tenant, id 1
+INSERT INTO tenants (name, created_at, updated_at) VALUES ('Default', SYSDATETIMEOFFSET(), SYSDATETIMEOFFSET());
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users ADD tenant_id INT NOT NULL CONSTRAINT df_users_tenant_id DEFAULT 1 CONSTRAINT fk_users_tenant REFERENCES tenants(id);
+ALTER TABLE departments ADD tenant_id INT NOT NULL CONSTRAINT df_departments_tenant_id DEFAULT 1 CONSTRAINT fk_departments_tenant REFERENCES tenants(id);
+ALTER TABLE employees ADD tenant_id INT NOT NULL CONSTRAINT df_employees_tenant_id DEFAULT 1 CONSTRAINT fk_employees_tenant REFERENCES tenants(id);
+ALTER TABLE leave_records ADD tenant_id INT NOT NULL CONSTRAINT df_leave_records_tenant_id DEFAULT 1 CONSTRAINT fk_leave_records_tenant REFERENCES tenants(id);
+ALTER TABLE api_keys ADD tenant_id INT NOT NULL CONSTRAINT df_api_keys_tenant_id DEFAULT 1 CONSTRAINT fk_api_keys_tenant REFERENCES tenants(id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX ix_users_tenant_id ON users (tenant_id);
+CREATE INDEX ix_departments_tenant_id ON departments (tenant_id);
+CREATE INDEX ix_employees_tenant_id ON employees (tenant_id);
+CREATE INDEX ix_leave_records_tenant_id ON leave_records (tenant_id);
+CREATE INDEX ix_api_keys_tenant_id ON api_keys (tenant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX ix_users_tenant_id ON users;
+DROP INDEX ix_departments_tenant_id ON departments;
+DROP INDEX ix_employees_tenant_id ON employees;
+DROP INDEX ix_leave_records_tenant_id ON leave_records;
+DROP INDEX ix_api_keys_tenant_id ON api_keys;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP CONSTRAINT fk_users_tenant, df_users_tenant_id;
+ALTER TABLE users DROP COLUMN tenant_id;
+ALTER TABLE departments DROP CONSTRAINT fk_departments_tenant, df_departments_tenant_id;
+ALTER TABLE departments DROP COLUMN tenant_id;
+ALTER TABLE employees DROP CONSTRAINT fk_employees_tenant, df_employees_tenant_id;
+ALTER TABLE employees DROP COLUMN tenant_id;
+ALTER TABLE leave_records DROP CONSTRAINT fk_leave_records_tenant, df_leave_records_tenant_id;
+ALTER TABLE leave_records DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP CONSTRAINT fk_api_keys_tenant, df_api_keys_tenant_id;
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE tenants;
+-- +goose StatementEnd
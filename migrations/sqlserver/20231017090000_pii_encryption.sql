@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- encrypted values are longer than the plaintext and dates are stored as text, existing rows are
+-- encrypted by `admin pii reencrypt`
+ALTER TABLE employees ALTER COLUMN email NVARCHAR(512) NOT NULL;
+ALTER TABLE employees ALTER COLUMN dob NVARCHAR(512) NULL;
+ALTER TABLE employees ADD email_index CHAR(64) NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX ix_employees_email_index ON employees (tenant_id, email_index);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX ix_employees_email_index ON employees;
+ALTER TABLE employees DROP COLUMN email_index;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- only unencrypted values convert back
+ALTER TABLE employees ALTER COLUMN dob DATE NULL;
+ALTER TABLE employees ALTER COLUMN email NVARCHAR(100) NOT NULL;
+-- +goose StatementEnd
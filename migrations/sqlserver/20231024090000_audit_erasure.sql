@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- audit entries outlive the users and employees they are about, the subject columns have no foreign key
+CREATE TABLE audit_entries (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    tenant_id INT NOT NULL REFERENCES tenants(id),
+    actor_id INT NULL,
+    action NVARCHAR(64) NOT NULL,
+    subject_user_id INT NULL,
+    subject_employee_id INT NULL,
+    detail NVARCHAR(512) NOT NULL,
+    created_at DATETIMEOFFSET NOT NULL
+);
+CREATE INDEX ix_audit_entries_subject_user_id ON audit_entries (tenant_id, subject_user_id);
+CREATE INDEX ix_audit_entries_subject_employee_id ON audit_entries (tenant_id, subject_employee_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE employees ADD erased_at DATETIMEOFFSET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE employees DROP COLUMN erased_at;
+DROP TABLE audit_entries;
+-- +goose StatementEnd
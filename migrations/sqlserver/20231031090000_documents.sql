@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE documents (
+    id INT PRIMARY KEY IDENTITY(1,1),
+    tenant_id INT NOT NULL REFERENCES tenants(id),
+    employee_id INT NOT NULL REFERENCES employees(id),
+    kind NVARCHAR(32) NOT NULL,
+    file_name NVARCHAR(255) NOT NULL,
+    content_type NVARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    storage_key NVARCHAR(255) NOT NULL CONSTRAINT uq_documents_storage_key UNIQUE,
+    uploaded_by INT NULL REFERENCES users(id),
+    created_at DATETIMEOFFSET NOT NULL
+);
+CREATE INDEX ix_documents_employee_id ON documents (tenant_id, employee_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE documents;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- sessions and recovery codes belong to the tenant of their user
+-- +goose StatementBegin
+ALTER TABLE sessions ADD tenant_id INT NOT NULL CONSTRAINT df_sessions_tenant_id DEFAULT 1 CONSTRAINT fk_sessions_tenant REFERENCES tenants(id);
+ALTER TABLE recovery_codes ADD tenant_id INT NOT NULL CONSTRAINT df_recovery_codes_tenant_id DEFAULT 1 CONSTRAINT fk_recovery_codes_tenant REFERENCES tenants(id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE sessions SET tenant_id = users.tenant_id FROM sessions JOIN users ON users.id = sessions.user_id;
+UPDATE recovery_codes SET tenant_id = users.tenant_id FROM recovery_codes JOIN users ON users.id = recovery_codes.user_id;
+CREATE INDEX ix_sessions_tenant_id ON sessions (tenant_id, user_id);
+CREATE INDEX ix_recovery_codes_tenant_id ON recovery_codes (tenant_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX ix_sessions_tenant_id ON sessions;
+DROP INDEX ix_recovery_codes_tenant_id ON recovery_codes;
+ALTER TABLE sessions DROP CONSTRAINT fk_sessions_tenant, df_sessions_tenant_id;
+ALTER TABLE sessions DROP COLUMN tenant_id;
+ALTER TABLE recovery_codes DROP CONSTRAINT fk_recovery_codes_tenant, df_recovery_codes_tenant_id;
+ALTER TABLE recovery_codes DROP COLUMN tenant_id;
+-- +goose StatementEnd
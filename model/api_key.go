@@ -0,0 +1,82 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyPrefix is prepended to every generated API key so leaked keys are easy to recognise
+	APIKeyPrefix       = "ems"
+	apiKeyIDLength     = 8
+	apiKeySecretLength = 32
+)
+
+// APIKeySecret is the plain text API key handed to a service client. Only its hash is persisted
+type APIKeySecret string
+
+// APIKey object, authenticates a service account User without a password
+type APIKey struct {
+	ID         int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID   int
+	UserID     int
+	Prefix     string
+	KeyHash    string
+	CreatedBy  int
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	User       User `gorm:"foreignKey:UserID"`
+}
+
+// NewAPIKeySecret generates a new random API key of the form ems_<prefix>_<secret>
+func NewAPIKeySecret() (APIKeySecret, error) {
+	id := make([]byte, apiKeyIDLength/2)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	secret := make([]byte, apiKeySecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return APIKeySecret(strings.Join([]string{
+		APIKeyPrefix,
+		hex.EncodeToString(id),
+		base64.RawURLEncoding.EncodeToString(secret),
+	}, "_")), nil
+}
+
+// String representation of the API key
+func (a APIKeySecret) String() string {
+	return string(a)
+}
+
+// Prefix returns the non secret part of the key used to identify it in listings
+func (a APIKeySecret) Prefix() string {
+	parts := strings.SplitN(a.String(), "_", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[0] + "_" + parts[1]
+}
+
+// Hash returns the hex encoded SHA-256 of the key. Keys carry 256 bits of entropy so a fast
+// hash is sufficient and allows looking the key up directly
+func (a APIKeySecret) Hash() string {
+	sum := sha256.Sum256([]byte(a))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active reports whether the key can still be used at the supplied time
+func (a APIKey) Active(at time.Time) bool {
+	if a.RevokedAt != nil {
+		return false
+	}
+	return a.ExpiresAt == nil || at.Before(*a.ExpiresAt)
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_APIKeySecret(t *testing.T) {
+	secret, err := NewAPIKeySecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret.String(), APIKeyPrefix+"_"))
+	require.Len(t, secret.Prefix(), len(APIKeyPrefix)+1+apiKeyIDLength)
+	require.True(t, strings.HasPrefix(secret.String(), secret.Prefix()+"_"))
+	require.Len(t, secret.Hash(), 64)
+
+	other, err := NewAPIKeySecret()
+	require.NoError(t, err)
+	require.NotEqual(t, secret.Hash(), other.Hash())
+
+	require.Empty(t, APIKeySecret("not-a-key").Prefix())
+}
+
+func Test_APIKey_Active(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	require.True(t, APIKey{}.Active(now))
+	require.True(t, APIKey{ExpiresAt: &future}.Active(now))
+	require.False(t, APIKey{ExpiresAt: &past}.Active(now))
+	require.False(t, APIKey{RevokedAt: &past}.Active(now))
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// AuditAction names what an AuditEntry records
+type AuditAction string
+
+const (
+	// AuditUserCreated an administrator created a user
+	AuditUserCreated AuditAction = "user.created"
+	// AuditPasswordReset an administrator replaced the password of a user
+	AuditPasswordReset AuditAction = "user.password_reset"
+	// AuditRoleChanged an administrator changed the role of a user
+	AuditRoleChanged AuditAction = "user.role_changed"
+	// AuditUserLocked an administrator locked a user
+	AuditUserLocked AuditAction = "user.locked"
+	// AuditUserUnlocked an administrator unlocked a user
+	AuditUserUnlocked AuditAction = "user.unlocked"
+	// AuditPersonalDataExported an administrator exported everything held about an employee
+	AuditPersonalDataExported AuditAction = "personal_data.exported"
+	// AuditPersonalDataErased an administrator erased the personal data of an employee
+	AuditPersonalDataErased AuditAction = "personal_data.erased"
+	// AuditDocumentUploaded a document about an employee was uploaded
+	AuditDocumentUploaded AuditAction = "document.uploaded"
+	// AuditDocumentDeleted a document about an employee was deleted
+	AuditDocumentDeleted AuditAction = "document.deleted"
+)
+
+// AuditEntry object, a change made by ActorID to a user or an employee. Entries are
+// never updated nor deleted, they outlive their subjects.
+type AuditEntry struct {
+	ID       int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID int
+	// ActorID, SubjectUserID and SubjectEmployeeID are optional, zero is stored as NULL. ActorID
+	// is zero for the admin command line.
+	ActorID           int `gorm:"default:null"`
+	Action            AuditAction
+	SubjectUserID     int `gorm:"default:null"`
+	SubjectEmployeeID int `gorm:"default:null"`
+	Detail            string
+	CreatedAt         time.Time
+}
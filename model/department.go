@@ -3,7 +3,8 @@ package model
 import "time"
 
 type Department struct {
-	ID             string `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	ID             int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID       int
 	DepartmentName string
 	UpdatedAt      time.Time
 	DeletedAt      time.Time
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// DocumentKind is what a Document is
+type DocumentKind string
+
+const (
+	// DocumentKindContract is an employment contract or an amendment to it
+	DocumentKindContract DocumentKind = "contract"
+	// DocumentKindIdentity is a scan of a passport, identity card or work permit
+	DocumentKindIdentity DocumentKind = "identity"
+	// DocumentKindOther is any other document about the employee
+	DocumentKindOther DocumentKind = "other"
+)
+
+// Document object, a file about an Employee. Its content is kept in the blob store under
+// StorageKey, Checksum is the hex encoded SHA-256 of it.
+type Document struct {
+	ID          int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID    int
+	EmployeeID  int
+	Kind        DocumentKind
+	FileName    string
+	ContentType string
+	Size        int64
+	Checksum    string
+	StorageKey  string
+	// UploadedBy is the user who uploaded it, zero for the admin command line is stored as NULL
+	UploadedBy int `gorm:"default:null"`
+	CreatedAt  time.Time
+}
+
+// Valid reports whether k is a known kind of document
+func (k DocumentKind) Valid() bool {
+	switch k {
+	case DocumentKindContract, DocumentKindIdentity, DocumentKindOther:
+		return true
+	}
+	return false
+}
@@ -1,16 +1,41 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	// registers the serializer encrypting the fields tagged pii
+	_ "employee-management-system/pkg/pii"
+)
 
 type Employee struct {
-	ID           int `gorm:"column:id;PRIMARY_KEY;type:int;"`
-	UserID       int
-	FirstName    string
-	LastName     string
-	Email        string
-	Dob          time.Time
-	DepartmentID int `gorm:"column:departmentID;FOREIGNKEY"`
+	ID       int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID int
+	// UserID, DepartmentID and ManagerID are optional, zero is stored as NULL
+	UserID    int `gorm:"default:null"`
+	FirstName string
+	LastName  string
+	// Email and Dob are encrypted at rest, storage looks Email up by its blind index EmailIndex
+	Email        string    `gorm:"serializer:pii"`
+	EmailIndex   string    `gorm:"default:null"`
+	Dob          time.Time `gorm:"serializer:pii"`
+	DepartmentID int       `gorm:"column:departmentID;FOREIGNKEY;default:null"`
 	Position     string
-	UpdatedAt    time.Time
-	DeletedAt    time.Time
+	// ManagerID is the Employee this one reports to
+	ManagerID int `gorm:"default:null"`
+	// PhotoKey prefixes the blob store keys of the profile photo thumbnails, empty without a photo
+	PhotoKey string `gorm:"default:null"`
+	// ErasedAt is set once the personal data of the employee was erased on request
+	ErasedAt  *time.Time
+	UpdatedAt time.Time
+	DeletedAt time.Time
+}
+
+// EmployeeFilter narrows down a list of employees, nil fields do not filter
+type EmployeeFilter struct {
+	DepartmentID *int
+	Position     *string
+	// Search matches first name or last name containing the value, and the whole email address
+	// when SearchEmail is set
+	Search      *string
+	SearchEmail bool
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// LeaveKind is the reason of an absence
+type LeaveKind string
+
+// LeaveStatus is where a leave request stands
+type LeaveStatus string
+
+const (
+	// LeaveKindAnnual is paid holiday
+	LeaveKindAnnual LeaveKind = "annual"
+	// LeaveKindSick is sick leave
+	LeaveKindSick LeaveKind = "sick"
+	// LeaveKindParental is maternity, paternity or adoption leave
+	LeaveKindParental LeaveKind = "parental"
+	// LeaveKindUnpaid is unpaid leave
+	LeaveKindUnpaid LeaveKind = "unpaid"
+
+	// LeaveStatusPending is waiting for the manager
+	LeaveStatusPending LeaveStatus = "pending"
+	// LeaveStatusApproved was accepted by the manager
+	LeaveStatusApproved LeaveStatus = "approved"
+	// LeaveStatusRejected was turned down by the manager
+	LeaveStatusRejected LeaveStatus = "rejected"
+)
+
+// LeaveRecord object, an absence of an Employee from StartsOn to EndsOn inclusive
+type LeaveRecord struct {
+	ID         int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID   int
+	EmployeeID int
+	Kind       LeaveKind
+	Status     LeaveStatus
+	StartsOn   time.Time
+	EndsOn     time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,89 @@
+package model
+
+import "time"
+
+// PersonalData is everything held about an employee and its user, handed to them on a data subject
+// access request as a JSON archive. Password hashes, TOTP secrets and API key hashes are left out,
+// they are credentials rather than data about the person.
+type PersonalData struct {
+	ExportedAt   time.Time             `json:"exported_at"`
+	Employee     PersonalEmployee      `json:"employee"`
+	User         *PersonalUser         `json:"user,omitempty"`
+	Sessions     []PersonalSession     `json:"sessions"`
+	APIKeys      []PersonalAPIKey      `json:"api_keys"`
+	LeaveRecords []PersonalLeaveRecord `json:"leave_records"`
+	Documents    []PersonalDocument    `json:"documents"`
+	AuditEntries []PersonalAuditEntry  `json:"audit_entries"`
+}
+
+// PersonalEmployee is the employee record of PersonalData
+type PersonalEmployee struct {
+	ID           int        `json:"id"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	Email        string     `json:"email"`
+	Dob          *time.Time `json:"dob,omitempty"`
+	DepartmentID int        `json:"department_id,omitempty"`
+	Position     string     `json:"position"`
+	ManagerID    int        `json:"manager_id,omitempty"`
+	HasPhoto     bool       `json:"has_photo"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	ErasedAt     *time.Time `json:"erased_at,omitempty"`
+}
+
+// PersonalUser is the account of PersonalData
+type PersonalUser struct {
+	ID               int        `json:"id"`
+	UserName         string     `json:"user_name"`
+	Role             string     `json:"role"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	LockedAt         *time.Time `json:"locked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// PersonalSession is a sign in of PersonalData
+type PersonalSession struct {
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// PersonalAPIKey is an API key of PersonalData, identified by its non secret prefix
+type PersonalAPIKey struct {
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// PersonalLeaveRecord is an absence of PersonalData
+type PersonalLeaveRecord struct {
+	Kind     LeaveKind   `json:"kind"`
+	Status   LeaveStatus `json:"status"`
+	StartsOn time.Time   `json:"starts_on"`
+	EndsOn   time.Time   `json:"ends_on"`
+}
+
+// PersonalDocument is the description of a document of PersonalData, its content is downloaded
+// separately
+type PersonalDocument struct {
+	ID          int          `json:"id"`
+	Kind        DocumentKind `json:"kind"`
+	FileName    string       `json:"file_name"`
+	ContentType string       `json:"content_type"`
+	Size        int64        `json:"size"`
+	Checksum    string       `json:"checksum"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// PersonalAuditEntry is an administrative change about the employee or its user
+type PersonalAuditEntry struct {
+	Action    AuditAction `json:"action"`
+	ActorID   int         `json:"actor_id"`
+	Detail    string      `json:"detail"`
+	CreatedAt time.Time   `json:"created_at"`
+}
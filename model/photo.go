@@ -0,0 +1,34 @@
+package model
+
+// PhotoSize is one of the square thumbnails a profile photo is stored as
+type PhotoSize string
+
+const (
+	// PhotoSizeSmall is for lists and avatars
+	PhotoSizeSmall PhotoSize = "small"
+	// PhotoSizeMedium is for directory cards
+	PhotoSizeMedium PhotoSize = "medium"
+	// PhotoSizeLarge is for the profile page
+	PhotoSizeLarge PhotoSize = "large"
+)
+
+// PhotoSizes lists every size a profile photo is stored as
+var PhotoSizes = []PhotoSize{PhotoSizeSmall, PhotoSizeMedium, PhotoSizeLarge}
+
+// Pixels returns the width and height of the thumbnail, zero for an unknown size
+func (s PhotoSize) Pixels() int {
+	switch s {
+	case PhotoSizeSmall:
+		return 64
+	case PhotoSizeMedium:
+		return 256
+	case PhotoSizeLarge:
+		return 512
+	}
+	return 0
+}
+
+// Valid reports whether s is a known size
+func (s PhotoSize) Valid() bool {
+	return s.Pixels() != 0
+}
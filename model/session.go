@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Session object, one per issued access/refresh token pair
+type Session struct {
+	ID         int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	TenantID   int
+	UserID     int
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// Active reports whether tokens of the session are still accepted at the supplied time
+func (s Session) Active(at time.Time) bool {
+	return s.RevokedAt == nil && at.Before(s.ExpiresAt)
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Tenant object, a company whose users, employees and departments are kept apart from every other
+type Tenant struct {
+	ID        int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
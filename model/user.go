@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -26,15 +27,37 @@ const (
 // User object
 type (
 	User struct {
-		ID        int `gorm:"column:id;PRIMARY_KEY;type:int;"`
-		UserName  *string
-		Password  Password
+		ID int `gorm:"column:id;PRIMARY_KEY;type:int;"`
+		// TenantID is set by storage from the context, user names are unique across tenants
+		TenantID int
+		UserName *string
+		Password Password
+		Kind     Kind
+		// ServiceAccount users have no password and authenticate with API keys only
+		ServiceAccount bool
+		// TOTPSecret is set once enrolment starts, TOTPEnabledAt once the first code is verified
+		TOTPSecret    *string    `gorm:"column:totp_secret"`
+		TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at"`
+		// TOTPLastStep is the last accepted time step, codes are never accepted twice
+		TOTPLastStep int64 `gorm:"column:totp_last_step"`
+		// LockedAt is set while an administrator has locked the account
+		LockedAt  *time.Time `gorm:"column:locked_at"`
 		CreatedAt time.Time
 		UpdatedAt time.Time
 		DeletedAt *gorm.DeletedAt
 	}
 )
 
+// TwoFactorEnabled reports whether the user completed TOTP enrolment
+func (u User) TwoFactorEnabled() bool {
+	return u.TOTPSecret != nil && u.TOTPEnabledAt != nil
+}
+
+// Locked reports whether the account is locked and may not sign in
+func (u User) Locked() bool {
+	return u.LockedAt != nil
+}
+
 // String representation of a user Kind int value
 func (k Kind) String() string {
 	return [...]string{
@@ -45,6 +68,16 @@ func (k Kind) String() string {
 	}[k]
 }
 
+// KindFromString returns the assignable Kind with the supplied name, ignoring case
+func KindFromString(name string) (Kind, bool) {
+	for _, kind := range []Kind{KindAdministrator, KindStaff} {
+		if strings.EqualFold(kind.String(), strings.TrimSpace(name)) {
+			return kind, true
+		}
+	}
+	return KindUnknown, false
+}
+
 // Value Get the int value of type Kind
 func (k Kind) Value() int {
 	return int(k)
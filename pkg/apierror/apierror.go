@@ -0,0 +1,99 @@
+// Package apierror maps application errors to the codes shared by the GraphQL and REST APIs, so a
+// client sees the same code for the same failure whichever API it calls
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+const (
+	// CodeBadUserInput the request is malformed or a value is invalid
+	CodeBadUserInput = "BAD_USER_INPUT"
+	// CodeUnauthenticated the caller is not signed in or its credentials are invalid
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	// CodeForbidden the caller may not perform the operation
+	CodeForbidden = "FORBIDDEN"
+	// CodeNotFound the record does not exist
+	CodeNotFound = "NOT_FOUND"
+	// CodeConflict the record already exists
+	CodeConflict = "CONFLICT"
+	// CodeRateLimited the caller sent too many requests
+	CodeRateLimited = "RATE_LIMITED"
+	// CodeInternal anything else
+	CodeInternal = "INTERNAL_SERVER_ERROR"
+)
+
+// Error carries an explicit code, for failures detected by the API layer itself
+type Error struct {
+	Code    string
+	Message string
+}
+
+// Error message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// BadInput returns a BAD_USER_INPUT error
+func BadInput(format string, args ...interface{}) error {
+	return &Error{Code: CodeBadUserInput, Message: fmt.Sprintf(format, args...)}
+}
+
+var codes = []struct {
+	err  error
+	code string
+}{
+	{storage.ErrRecordNotFound, CodeNotFound},
+	{storage.ErrEmptyResult, CodeNotFound},
+	{storage.ErrDuplicateRecord, CodeConflict},
+	{storage.ErrUnauthorizedAccess, CodeForbidden},
+	{middleware.ErrUnauthorized, CodeUnauthenticated},
+	{middleware.ErrInvalidToken, CodeUnauthenticated},
+	{middleware.ErrInvalidAPIKey, CodeUnauthenticated},
+	{middleware.ErrSessionRevoked, CodeUnauthenticated},
+	{middleware.ErrFailedAuthentication, CodeUnauthenticated},
+	{middleware.ErrAccountSuspended, CodeForbidden},
+	{middleware.ErrInvalidTwoFactorCode, CodeBadUserInput},
+	{middleware.ErrTwoFactorAlreadyEnabled, CodeConflict},
+	{middleware.ErrTwoFactorNotEnrolled, CodeBadUserInput},
+	{middleware.ErrTwoFactorRequired, CodeForbidden},
+}
+
+// Code returns the API error code of err
+func Code(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeInternal
+}
+
+// Status returns the HTTP status a REST response with code answers with
+func Status(code string) int {
+	switch code {
+	case CodeBadUserInput:
+		return http.StatusBadRequest
+	case CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case CodeForbidden:
+		return http.StatusForbidden
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeConflict:
+		return http.StatusConflict
+	case CodeRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,61 @@
+// Package blob stores the contents of documents by key, in a local directory or in an S3
+// compatible object store
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"employee-management-system/pkg/config"
+)
+
+var (
+	// ErrNotFound no blob is stored under the key
+	ErrNotFound = errors.New("blob: not found")
+	// ErrInvalidKey the key is empty or escapes the store
+	ErrInvalidKey = errors.New("blob: invalid key")
+)
+
+// Store keeps blobs by key. Keys are slash separated paths of letters, digits, dots, dashes and
+// underscores.
+type Store interface {
+	// Put stores the size bytes of r under key, replacing any blob stored there
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get returns the blob stored under key, the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key, a missing one is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the store configured by cfg
+func New(cfg config.Documents) (Store, error) {
+	switch cfg.Store {
+	case config.DocumentStoreLocal:
+		return NewLocal(cfg.Dir)
+	case config.DocumentStoreS3:
+		return NewS3(cfg.S3, nil)
+	default:
+		return nil, fmt.Errorf("blob: unsupported store %q", cfg.Store)
+	}
+}
+
+// validKey reports whether key is a relative path without empty, . or .. segments
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,613 @@
+// Package config defines the typed application configuration and how it is loaded and validated
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"employee-management-system/model"
+)
+
+const (
+	// TracingExporterNone disables tracing
+	TracingExporterNone = "none"
+	// TracingExporterStdout writes spans to stdout, for local debugging
+	TracingExporterStdout = "stdout"
+	// TracingExporterOTLP sends spans to an OTLP/HTTP collector
+	TracingExporterOTLP = "otlp"
+
+	// DatabaseDriverSQLServer connects to SQL Server or Azure SQL Edge
+	DatabaseDriverSQLServer = "sqlserver"
+	// DatabaseDriverPostgres connects to PostgreSQL
+	DatabaseDriverPostgres = "postgres"
+	// DatabaseDriverSQLite opens the SQLite file named by the database name, for development and tests
+	DatabaseDriverSQLite = "sqlite"
+
+	// DocumentStoreLocal keeps documents in a directory of the local filesystem
+	DocumentStoreLocal = "local"
+	// DocumentStoreS3 keeps documents in a bucket of an S3 compatible object store
+	DocumentStoreS3 = "s3"
+)
+
+type (
+	// Config is the complete application configuration. Every leaf value can be set, in increasing
+	// order of precedence, by its default, the optional YAML/TOML file, the .env file, the
+	// environment variable named by its env tag and the command line flag named by its flag tag.
+	Config struct {
+		Server    Server    `yaml:"server" toml:"server"`
+		Database  Database  `yaml:"database" toml:"database"`
+		JWT       JWT       `yaml:"jwt" toml:"jwt"`
+		TwoFactor TwoFactor `yaml:"two_factor" toml:"two_factor"`
+		Health    Health    `yaml:"health" toml:"health"`
+		Metrics   Metrics   `yaml:"metrics" toml:"metrics"`
+		Tracing   Tracing   `yaml:"tracing" toml:"tracing"`
+		RateLimit RateLimit `yaml:"rate_limit" toml:"rate_limit"`
+		GraphQL   GraphQL   `yaml:"graphql" toml:"graphql"`
+		GRPC      GRPC      `yaml:"grpc" toml:"grpc"`
+		PII       PII       `yaml:"pii" toml:"pii"`
+		Documents Documents `yaml:"documents" toml:"documents"`
+	}
+
+	// Server holds the HTTP server settings
+	Server struct {
+		Port              int      `yaml:"port" toml:"port" env:"PORT" flag:"port"`
+		ReadTimeout       Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT" flag:"read-timeout"`
+		ReadHeaderTimeout Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" flag:"read-header-timeout"`
+		WriteTimeout      Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" flag:"write-timeout"`
+		IdleTimeout       Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" flag:"idle-timeout"`
+		// ShutdownTimeout bounds how long in-flight requests and subscriptions are drained for
+		ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" flag:"shutdown-timeout"`
+	}
+
+	// Database holds the connection settings, the MSSQL_* variables apply to every driver
+	Database struct {
+		// Driver is sqlserver, postgres or sqlite, it also selects the migrations applied
+		Driver   string `yaml:"driver" toml:"driver" env:"DATABASE_DRIVER" flag:"db-driver"`
+		Host     string `yaml:"host" toml:"host" env:"MSSQL_ADDRESS" flag:"db-host"`
+		Port     int    `yaml:"port" toml:"port" env:"MSSQL_PORT" flag:"db-port"`
+		User     string `yaml:"user" toml:"user" env:"MSSQL_USER" flag:"db-user"`
+		Password string `yaml:"password" toml:"password" env:"MSSQL_PASSWORD" flag:"db-password"`
+		Name     string `yaml:"name" toml:"name" env:"MSSQL_DATABASE" flag:"db-name"`
+		// SSLMode and TimeZone only apply to postgres
+		SSLMode  string `yaml:"ssl_mode" toml:"ssl_mode" env:"DATABASE_SSL_MODE" flag:"db-ssl-mode"`
+		TimeZone string `yaml:"time_zone" toml:"time_zone" env:"DATABASE_TIME_ZONE" flag:"db-time-zone"`
+		// DSN when set is used as is instead of the individual settings above
+		DSN string `yaml:"dsn" toml:"dsn" env:"DATABASE_DSN" flag:"dsn"`
+		// AutoMigrate applies the pending migrations on startup
+		AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"DATABASE_AUTO_MIGRATE" flag:"migrate"`
+		// MigrationLockTimeout bounds how long startup waits for another instance to finish migrating
+		MigrationLockTimeout Duration `yaml:"migration_lock_timeout" toml:"migration_lock_timeout" env:"DATABASE_MIGRATION_LOCK_TIMEOUT" flag:"migration-lock-timeout"`
+	}
+
+	// JWT holds the token signing settings
+	JWT struct {
+		SigningSecret    string `yaml:"signing_secret" toml:"signing_secret" env:"SIGNING_SECRET_KEY" flag:"jwt-secret"`
+		SigningAlgorithm string `yaml:"signing_algorithm" toml:"signing_algorithm" env:"JWT_SIGNING_ALGORITHM" flag:"jwt-algorithm"`
+		PrivateKeyFile   string `yaml:"private_key_file" toml:"private_key_file" env:"JWT_PRIVATE_KEY_FILE" flag:"jwt-private-key"`
+		KeyID            string `yaml:"key_id" toml:"key_id" env:"JWT_KEY_ID" flag:"jwt-key-id"`
+		// VerificationKeys lists previous keys still accepted during rotation as kid=path pairs
+		VerificationKeys   []string    `yaml:"verification_keys" toml:"verification_keys" env:"JWT_VERIFICATION_KEYS" flag:"jwt-verification-keys"`
+		AccessTokenExpiry  TokenExpiry `yaml:"access_token_expiry" toml:"access_token_expiry" env:"JWT_ACCESS_TOKEN_EXPIRY" flag:"jwt-access-expiry"`
+		RefreshTokenExpiry TokenExpiry `yaml:"refresh_token_expiry" toml:"refresh_token_expiry" env:"JWT_REFRESH_TOKEN_EXPIRY" flag:"jwt-refresh-expiry"`
+	}
+
+	// TwoFactor holds the TOTP settings
+	TwoFactor struct {
+		RequiredRoles []string `yaml:"required_roles" toml:"required_roles" env:"TWO_FACTOR_REQUIRED_ROLES" flag:"two-factor-roles"`
+		Issuer        string   `yaml:"issuer" toml:"issuer" env:"TWO_FACTOR_ISSUER" flag:"two-factor-issuer"`
+	}
+
+	// Health holds the readiness check settings
+	Health struct {
+		CheckTimeout Duration `yaml:"check_timeout" toml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" flag:"health-check-timeout"`
+		// CheckMigrations reports the service unready while the database misses embedded migrations
+		CheckMigrations bool `yaml:"check_migrations" toml:"check_migrations" env:"HEALTH_CHECK_MIGRATIONS" flag:"health-check-migrations"`
+		// Downstream lists further services that must be reachable as name=url pairs
+		Downstream []string `yaml:"downstream" toml:"downstream" env:"HEALTH_DOWNSTREAM" flag:"health-downstream"`
+	}
+
+	// Metrics holds the Prometheus endpoint settings
+	Metrics struct {
+		// Path the metrics are served on, empty disables the endpoint
+		Path string `yaml:"path" toml:"path" env:"METRICS_PATH" flag:"metrics-path"`
+	}
+
+	// Tracing holds the OpenTelemetry settings
+	Tracing struct {
+		// Exporter is none, stdout or otlp
+		Exporter    string `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" flag:"tracing-exporter"`
+		ServiceName string `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME" flag:"tracing-service-name"`
+		// Endpoint of the OTLP/HTTP collector as host:port or a http(s):// URL
+		Endpoint string `yaml:"endpoint" toml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" flag:"tracing-endpoint"`
+		Insecure bool   `yaml:"insecure" toml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE" flag:"tracing-insecure"`
+		// SampleRatio of new traces recorded, between 0 and 1. Sampled parents are always followed.
+		SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" flag:"tracing-sample-ratio"`
+	}
+
+	// RateLimit holds the token bucket settings of the GraphQL endpoint
+	RateLimit struct {
+		Enabled           bool `yaml:"enabled" toml:"enabled" env:"RATE_LIMIT_ENABLED" flag:"rate-limit"`
+		RequestsPerMinute int  `yaml:"requests_per_minute" toml:"requests_per_minute" env:"RATE_LIMIT_REQUESTS_PER_MINUTE" flag:"rate-limit-rpm"`
+		Burst             int  `yaml:"burst" toml:"burst" env:"RATE_LIMIT_BURST" flag:"rate-limit-burst"`
+		// AuthRequestsPerMinute and AuthBurst limit AuthOperations per client IP on top of the general limit
+		AuthRequestsPerMinute int      `yaml:"auth_requests_per_minute" toml:"auth_requests_per_minute" env:"RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE" flag:"rate-limit-auth-rpm"`
+		AuthBurst             int      `yaml:"auth_burst" toml:"auth_burst" env:"RATE_LIMIT_AUTH_BURST" flag:"rate-limit-auth-burst"`
+		AuthOperations        []string `yaml:"auth_operations" toml:"auth_operations" env:"RATE_LIMIT_AUTH_OPERATIONS" flag:"rate-limit-auth-operations"`
+	}
+
+	// GraphQL holds the query cost limits and persisted query settings
+	GraphQL struct {
+		ComplexityLimit int `yaml:"complexity_limit" toml:"complexity_limit" env:"GRAPHQL_COMPLEXITY_LIMIT" flag:"graphql-complexity-limit"`
+		// ListMultiplier is the estimated number of elements of a list field
+		ListMultiplier int `yaml:"list_multiplier" toml:"list_multiplier" env:"GRAPHQL_LIST_MULTIPLIER" flag:"graphql-list-multiplier"`
+		DepthLimit     int `yaml:"depth_limit" toml:"depth_limit" env:"GRAPHQL_DEPTH_LIMIT" flag:"graphql-depth-limit"`
+		// APQCacheSize is the number of automatic persisted queries remembered
+		APQCacheSize int `yaml:"apq_cache_size" toml:"apq_cache_size" env:"GRAPHQL_APQ_CACHE_SIZE" flag:"graphql-apq-cache-size"`
+		// PersistedQueriesOnly replaces automatic persisted queries with the PersistedQueriesFile allow list
+		PersistedQueriesOnly bool   `yaml:"persisted_queries_only" toml:"persisted_queries_only" env:"GRAPHQL_PERSISTED_QUERIES_ONLY" flag:"graphql-persisted-queries-only"`
+		PersistedQueriesFile string `yaml:"persisted_queries_file" toml:"persisted_queries_file" env:"GRAPHQL_PERSISTED_QUERIES_FILE" flag:"graphql-persisted-queries-file"`
+	}
+
+	// GRPC holds the gRPC server settings
+	GRPC struct {
+		// Port the gRPC server listens on, 0 disables it
+		Port int `yaml:"port" toml:"port" env:"GRPC_PORT" flag:"grpc-port"`
+		// Reflection lets tools such as grpcurl discover the services
+		Reflection bool `yaml:"reflection" toml:"reflection" env:"GRPC_REFLECTION" flag:"grpc-reflection"`
+	}
+
+	// PII holds the encryption settings of personal data columns
+	PII struct {
+		// KeyringFile holds the data keys, personal data is stored unencrypted when it is empty
+		KeyringFile string `yaml:"keyring_file" toml:"keyring_file" env:"PII_KEYRING_FILE" flag:"pii-keyring"`
+	}
+
+	// Documents holds the employee document storage settings
+	Documents struct {
+		// Store is local or s3
+		Store string `yaml:"store" toml:"store" env:"DOCUMENTS_STORE" flag:"documents-store"`
+		// Dir the local store keeps the files in
+		Dir string `yaml:"dir" toml:"dir" env:"DOCUMENTS_DIR" flag:"documents-dir"`
+		// MaxSize is the largest accepted document upload in bytes
+		MaxSize int `yaml:"max_size" toml:"max_size" env:"DOCUMENTS_MAX_SIZE" flag:"documents-max-size"`
+		// PhotoMaxSize is the largest accepted profile photo upload in bytes
+		PhotoMaxSize int `yaml:"photo_max_size" toml:"photo_max_size" env:"DOCUMENTS_PHOTO_MAX_SIZE" flag:"documents-photo-max-size"`
+		// URLExpiry is how long a download URL stays valid
+		URLExpiry Duration `yaml:"url_expiry" toml:"url_expiry" env:"DOCUMENTS_URL_EXPIRY" flag:"documents-url-expiry"`
+		// URLSecret signs the download URLs, every instance must share it. When empty a random one
+		// is made at startup and URLs only work on the instance that issued them until it restarts.
+		URLSecret string `yaml:"url_secret" toml:"url_secret" env:"DOCUMENTS_URL_SECRET" flag:"documents-url-secret"`
+		S3        S3     `yaml:"s3" toml:"s3"`
+	}
+
+	// S3 holds the settings of an S3 compatible object store, addressed path-style
+	S3 struct {
+		// Endpoint is the base URL of the store, such as https://s3.eu-west-1.amazonaws.com
+		Endpoint        string `yaml:"endpoint" toml:"endpoint" env:"S3_ENDPOINT" flag:"s3-endpoint"`
+		Region          string `yaml:"region" toml:"region" env:"S3_REGION" flag:"s3-region"`
+		Bucket          string `yaml:"bucket" toml:"bucket" env:"S3_BUCKET" flag:"s3-bucket"`
+		AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id" env:"S3_ACCESS_KEY_ID" flag:"s3-access-key-id"`
+		SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key" env:"S3_SECRET_ACCESS_KEY" flag:"s3-secret-access-key"`
+	}
+
+	// Duration is a time.Duration set from a Go duration such as "90m"
+	Duration time.Duration
+
+	// TokenExpiry is a Duration that also accepts a bare number of minutes, as the
+	// JWT_*_TOKEN_EXPIRY variables always have
+	TokenExpiry Duration
+)
+
+// AsymmetricAlgorithms are the token signing algorithms that sign with a private key rather than a
+// shared secret
+var AsymmetricAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// IsAsymmetricAlgorithm reports whether alg is one of AsymmetricAlgorithms
+func IsAsymmetricAlgorithm(alg string) bool {
+	for _, asymmetric := range AsymmetricAlgorithms {
+		if alg == asymmetric {
+			return true
+		}
+	}
+	return false
+}
+
+// Default returns the configuration used when nothing overrides it
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:              8080,
+			ReadTimeout:       Duration(time.Second * 30),
+			ReadHeaderTimeout: Duration(time.Second * 10),
+			WriteTimeout:      Duration(time.Second * 60),
+			IdleTimeout:       Duration(time.Second * 120),
+			ShutdownTimeout:   Duration(time.Second * 30),
+		},
+		Database: Database{
+			Driver:   DatabaseDriverSQLServer,
+			Host:     "localhost",
+			Port:     1433,
+			Name:     "CompanyDB",
+			SSLMode:  "disable",
+			TimeZone: "UTC",
+
+			MigrationLockTimeout: Duration(time.Minute),
+		},
+		JWT: JWT{
+			SigningAlgorithm:   "HS256",
+			AccessTokenExpiry:  TokenExpiry(time.Minute * 15),
+			RefreshTokenExpiry: TokenExpiry(time.Hour * 24),
+		},
+		TwoFactor: TwoFactor{
+			Issuer: "Company",
+		},
+		Health: Health{
+			CheckTimeout:    Duration(time.Second * 2),
+			CheckMigrations: true,
+		},
+		Metrics: Metrics{
+			Path: "/metrics",
+		},
+		Tracing: Tracing{
+			Exporter:    TracingExporterNone,
+			ServiceName: "employee-management-system",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			Enabled:               true,
+			RequestsPerMinute:     300,
+			Burst:                 60,
+			AuthRequestsPerMinute: 5,
+			AuthBurst:             5,
+			AuthOperations:        []string{"login", "enrollTwoFactor", "verifyTwoFactor", "disableTwoFactor"},
+		},
+		GraphQL: GraphQL{
+			ComplexityLimit: 1000,
+			ListMultiplier:  20,
+			DepthLimit:      10,
+			APQCacheSize:    1000,
+		},
+		GRPC: GRPC{
+			Port:       9090,
+			Reflection: true,
+		},
+		Documents: Documents{
+			Store:        DocumentStoreLocal,
+			Dir:          "documents",
+			MaxSize:      10 << 20,
+			PhotoMaxSize: 5 << 20,
+			URLExpiry:    Duration(time.Minute * 5),
+			S3: S3{
+				Region: "us-east-1",
+			},
+		},
+	}
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	return errors.Join(
+		c.Server.Validate(),
+		c.Database.Validate(),
+		c.JWT.Validate(),
+		c.TwoFactor.Validate(),
+		c.Health.Validate(),
+		c.Tracing.Validate(),
+		c.RateLimit.Validate(),
+		c.GraphQL.Validate(),
+		c.GRPC.Validate(),
+		c.Documents.Validate(),
+		c.validatePorts(),
+	)
+}
+
+// validatePorts reports servers configured on the same port
+func (c *Config) validatePorts() error {
+	if c.GRPC.Port != 0 && c.GRPC.Port == c.Server.Port {
+		return fmt.Errorf("grpc port %d is already used by the http server (GRPC_PORT)", c.GRPC.Port)
+	}
+	return nil
+}
+
+// Validate reports every invalid server setting
+func (s Server) Validate() error {
+	var errs []error
+	if s.Port <= 0 || s.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server port %d is out of range (PORT)", s.Port))
+	}
+	if s.ReadTimeout < 0 || s.ReadHeaderTimeout < 0 || s.WriteTimeout < 0 || s.IdleTimeout < 0 {
+		errs = append(errs, errors.New("server timeouts must not be negative (SERVER_*_TIMEOUT)"))
+	}
+	if s.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server shutdown timeout must be positive (SERVER_SHUTDOWN_TIMEOUT)"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid database setting
+func (d Database) Validate() error {
+	var errs []error
+	if d.MigrationLockTimeout <= 0 {
+		errs = append(errs, errors.New("database migration lock timeout must be positive (DATABASE_MIGRATION_LOCK_TIMEOUT)"))
+	}
+	switch d.Driver {
+	case DatabaseDriverSQLServer, DatabaseDriverPostgres:
+	case DatabaseDriverSQLite:
+		if d.DSN == "" && d.Name == "" {
+			errs = append(errs, errors.New("database name, the SQLite file, is required (MSSQL_DATABASE)"))
+		}
+		return errors.Join(errs...)
+	default:
+		errs = append(errs, fmt.Errorf("unsupported database driver %q (DATABASE_DRIVER)", d.Driver))
+	}
+	if d.DSN != "" {
+		return errors.Join(errs...)
+	}
+
+	if d.Host == "" {
+		errs = append(errs, errors.New("database host is required (MSSQL_ADDRESS)"))
+	}
+	if d.Port <= 0 || d.Port > 65535 {
+		errs = append(errs, fmt.Errorf("database port %d is out of range (MSSQL_PORT)", d.Port))
+	}
+	if d.User == "" {
+		errs = append(errs, errors.New("database user is required (MSSQL_USER)"))
+	}
+	if d.Name == "" {
+		errs = append(errs, errors.New("database name is required (MSSQL_DATABASE)"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid token signing setting
+func (j JWT) Validate() error {
+	var errs []error
+	switch alg := j.SigningAlgorithm; {
+	case alg == "HS256" || alg == "HS384" || alg == "HS512":
+		if j.SigningSecret == "" {
+			errs = append(errs, fmt.Errorf("jwt signing secret is required for %s (SIGNING_SECRET_KEY)", alg))
+		}
+	case IsAsymmetricAlgorithm(alg):
+		if j.PrivateKeyFile == "" {
+			errs = append(errs, fmt.Errorf("jwt private key file is required for %s (JWT_PRIVATE_KEY_FILE)", alg))
+		} else if _, err := os.Stat(j.PrivateKeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("jwt private key file: %w", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported jwt signing algorithm %q (JWT_SIGNING_ALGORITHM)", alg))
+	}
+
+	for _, entry := range j.VerificationKeys {
+		if kid, path, ok := strings.Cut(entry, "="); !ok || kid == "" || path == "" {
+			errs = append(errs, fmt.Errorf("invalid jwt verification key %q, expected kid=path (JWT_VERIFICATION_KEYS)", entry))
+		}
+	}
+	if j.AccessTokenExpiry <= 0 {
+		errs = append(errs, errors.New("jwt access token expiry must be positive (JWT_ACCESS_TOKEN_EXPIRY)"))
+	}
+	if j.RefreshTokenExpiry < j.AccessTokenExpiry {
+		errs = append(errs, errors.New("jwt refresh token expiry must not be shorter than the access token expiry (JWT_REFRESH_TOKEN_EXPIRY)"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid two-factor setting
+func (t TwoFactor) Validate() error {
+	var errs []error
+	for _, role := range t.RequiredRoles {
+		if _, ok := model.KindFromString(role); !ok {
+			errs = append(errs, fmt.Errorf("unknown two-factor role %q (TWO_FACTOR_REQUIRED_ROLES)", role))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid readiness check setting
+func (h Health) Validate() error {
+	var errs []error
+	if h.CheckTimeout <= 0 {
+		errs = append(errs, errors.New("health check timeout must be positive (HEALTH_CHECK_TIMEOUT)"))
+	}
+	for _, entry := range h.Downstream {
+		name, target, ok := strings.Cut(entry, "=")
+		if u, err := url.Parse(target); !ok || name == "" || err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid downstream check %q, expected name=url (HEALTH_DOWNSTREAM)", entry))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid tracing setting
+func (t Tracing) Validate() error {
+	var errs []error
+	switch t.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterOTLP:
+		if t.Endpoint == "" {
+			errs = append(errs, errors.New("tracing endpoint is required for the otlp exporter (OTEL_EXPORTER_OTLP_ENDPOINT)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported tracing exporter %q, use none, stdout or otlp (TRACING_EXPORTER)", t.Exporter))
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing sample ratio %v must be between 0 and 1 (TRACING_SAMPLE_RATIO)", t.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid rate limit setting
+func (r RateLimit) Validate() error {
+	if !r.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if r.RequestsPerMinute <= 0 || r.Burst <= 0 {
+		errs = append(errs, errors.New("rate limit requests per minute and burst must be positive (RATE_LIMIT_REQUESTS_PER_MINUTE, RATE_LIMIT_BURST)"))
+	}
+	if r.AuthRequestsPerMinute <= 0 || r.AuthBurst <= 0 {
+		errs = append(errs, errors.New("rate limit auth requests per minute and burst must be positive (RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE, RATE_LIMIT_AUTH_BURST)"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid GraphQL setting
+func (g GraphQL) Validate() error {
+	var errs []error
+	if g.ComplexityLimit <= 0 || g.ListMultiplier <= 0 || g.DepthLimit <= 0 {
+		errs = append(errs, errors.New("graphql complexity limit, list multiplier and depth limit must be positive (GRAPHQL_*)"))
+	}
+	if g.PersistedQueriesOnly {
+		if g.PersistedQueriesFile == "" {
+			errs = append(errs, errors.New("graphql persisted queries file is required in persisted queries only mode (GRAPHQL_PERSISTED_QUERIES_FILE)"))
+		}
+	} else if g.APQCacheSize <= 0 {
+		errs = append(errs, errors.New("graphql apq cache size must be positive (GRAPHQL_APQ_CACHE_SIZE)"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid gRPC setting
+func (g GRPC) Validate() error {
+	if g.Port < 0 || g.Port > 65535 {
+		return fmt.Errorf("grpc port %d is out of range (GRPC_PORT)", g.Port)
+	}
+	return nil
+}
+
+// ConnectionString returns the data source name of the configured database in the form its driver expects
+func (d Database) ConnectionString() string {
+	if d.DSN != "" {
+		return d.DSN
+	}
+	switch d.Driver {
+	case DatabaseDriverSQLite:
+		return d.Name
+	case DatabaseDriverPostgres:
+		u := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(d.User, d.Password),
+			Host:     net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+			Path:     "/" + d.Name,
+			RawQuery: url.Values{"sslmode": {d.SSLMode}, "TimeZone": {d.TimeZone}}.Encode(),
+		}
+		return u.String()
+	}
+	u := url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+		RawQuery: url.Values{"database": {d.Name}}.Encode(),
+	}
+	return u.String()
+}
+
+// Address returns the address the HTTP server listens on
+func (s Server) Address() string {
+	return ":" + strconv.Itoa(s.Port)
+}
+
+// Address returns the address the gRPC server listens on
+func (g GRPC) Address() string {
+	return ":" + strconv.Itoa(g.Port)
+}
+
+// Duration returns the value as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String representation of the Duration
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// UnmarshalText parses a Go duration such as "90m"
+func (d *Duration) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected a value such as 90m", s)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the Duration so it round trips through UnmarshalText
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Duration returns the value as a time.Duration
+func (t TokenExpiry) Duration() time.Duration {
+	return time.Duration(t)
+}
+
+// String representation of the TokenExpiry
+func (t TokenExpiry) String() string {
+	return time.Duration(t).String()
+}
+
+// UnmarshalText parses a Go duration such as "90m" or a bare number of minutes
+func (t *TokenExpiry) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if minutes, err := strconv.Atoi(s); err == nil {
+		*t = TokenExpiry(time.Duration(minutes) * time.Minute)
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected minutes or a value such as 90m", s)
+	}
+	*t = TokenExpiry(parsed)
+	return nil
+}
+
+// MarshalText formats the TokenExpiry so it round trips through UnmarshalText
+func (t TokenExpiry) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// MaxUploadSize returns the size of the largest file accepted, a document or a photo
+func (d Documents) MaxUploadSize() int {
+	if d.PhotoMaxSize > d.MaxSize {
+		return d.PhotoMaxSize
+	}
+	return d.MaxSize
+}
+
+// Validate reports every invalid document storage setting
+func (d Documents) Validate() error {
+	var errs []error
+	switch d.Store {
+	case DocumentStoreLocal:
+		if d.Dir == "" {
+			errs = append(errs, errors.New("documents dir is required for the local store (DOCUMENTS_DIR)"))
+		}
+	case DocumentStoreS3:
+		if u, err := url.Parse(d.S3.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid s3 endpoint %q, expected a http(s):// URL (S3_ENDPOINT)", d.S3.Endpoint))
+		}
+		if d.S3.Region == "" || d.S3.Bucket == "" || d.S3.AccessKeyID == "" || d.S3.SecretAccessKey == "" {
+			errs = append(errs, errors.New("s3 region, bucket, access key id and secret access key are required for the s3 store (S3_*)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported documents store %q, use local or s3 (DOCUMENTS_STORE)", d.Store))
+	}
+	if d.MaxSize <= 0 {
+		errs = append(errs, errors.New("documents max size must be positive (DOCUMENTS_MAX_SIZE)"))
+	}
+	if d.PhotoMaxSize <= 0 {
+		errs = append(errs, errors.New("documents photo max size must be positive (DOCUMENTS_PHOTO_MAX_SIZE)"))
+	}
+	if d.URLExpiry <= 0 {
+		errs = append(errs, errors.New("documents url expiry must be positive (DOCUMENTS_URL_EXPIRY)"))
+	}
+	return errors.Join(errs...)
+}
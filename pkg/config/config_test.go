@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func Test_Load_Precedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+database:
+  host: file-host
+  user: file-user
+jwt:
+  access_token_expiry: 30m
+`)
+	t.Setenv("MSSQL_ADDRESS", "env-host")
+	t.Setenv("MSSQL_PORT", "1500")
+
+	cfg, args, err := Load("test", []string{"-config", file, "-db-port", "1600", "up"}, filepath.Join(t.TempDir(), ".env"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"up"}, args)
+
+	require.Equal(t, 9000, cfg.Server.Port)
+	require.Equal(t, "file-user", cfg.Database.User)
+	require.Equal(t, "env-host", cfg.Database.Host)
+	require.Equal(t, 1600, cfg.Database.Port)
+	require.Equal(t, "CompanyDB", cfg.Database.Name)
+	require.Equal(t, time.Minute*30, cfg.JWT.AccessTokenExpiry.Duration())
+}
+
+func Test_Load_TOML(t *testing.T) {
+	file := writeFile(t, "config.toml", `
+[two_factor]
+required_roles = ["Administrator"]
+issuer = "Acme"
+`)
+	t.Setenv(FileEnv, file)
+	t.Setenv("TWO_FACTOR_ISSUER", "")
+
+	cfg, _, err := Load("test", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Administrator"}, cfg.TwoFactor.RequiredRoles)
+	require.Equal(t, "", cfg.TwoFactor.Issuer)
+}
+
+func Test_Load_InvalidValue(t *testing.T) {
+	t.Setenv("JWT_REFRESH_TOKEN_EXPIRY", "tomorrow")
+
+	_, _, err := Load("test", nil, "")
+	require.ErrorContains(t, err, "JWT_REFRESH_TOKEN_EXPIRY")
+}
+
+func Test_Duration_UnmarshalText(t *testing.T) {
+	for text, want := range map[string]time.Duration{
+		"90m":  time.Minute * 90,
+		" 1h ": time.Hour,
+	} {
+		var d Duration
+		require.NoError(t, d.UnmarshalText([]byte(text)), text)
+		require.Equal(t, want, d.Duration(), text)
+	}
+
+	var d Duration
+	require.Error(t, d.UnmarshalText([]byte("soon")))
+	// only the token expiries take bare minutes
+	require.Error(t, d.UnmarshalText([]byte("30")))
+
+	var e TokenExpiry
+	require.NoError(t, e.UnmarshalText([]byte("14400")))
+	require.Equal(t, time.Hour*240, e.Duration())
+	require.NoError(t, e.UnmarshalText([]byte("90m")))
+	require.Equal(t, time.Minute*90, e.Duration())
+	require.Error(t, e.UnmarshalText([]byte("soon")))
+}
+
+func Test_Validate(t *testing.T) {
+	cfg := Default()
+	cfg.Database.User = "SA"
+	cfg.JWT.SigningSecret = "secret"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Server.Port = 0
+	cfg.Database.Host = ""
+	cfg.JWT.SigningSecret = ""
+	cfg.JWT.VerificationKeys = []string{"missing-path"}
+	cfg.TwoFactor.RequiredRoles = []string{"Janitor"}
+	cfg.Documents.Store = DocumentStoreS3
+	cfg.Documents.PhotoMaxSize = 0
+
+	err := cfg.Validate()
+	for _, want := range []string{"PORT", "MSSQL_ADDRESS", "SIGNING_SECRET_KEY", "JWT_VERIFICATION_KEYS", "TWO_FACTOR_REQUIRED_ROLES", "S3_ENDPOINT", "DOCUMENTS_PHOTO_MAX_SIZE"} {
+		require.ErrorContains(t, err, want)
+	}
+}
+
+func Test_ConnectionString(t *testing.T) {
+	d := Database{Host: "db", Port: 1433, User: "SA", Password: "p@ss word", Name: "CompanyDB"}
+	require.Equal(t, "sqlserver://SA:p%40ss%20word@db:1433?database=CompanyDB", d.ConnectionString())
+
+	d.DSN = "sqlserver://other"
+	require.Equal(t, "sqlserver://other", d.ConnectionString())
+
+	d = Database{Driver: DatabaseDriverPostgres, Host: "db", Port: 5432, User: "app", Password: "secret", Name: "CompanyDB", SSLMode: "disable", TimeZone: "UTC"}
+	require.Equal(t, "postgres://app:secret@db:5432/CompanyDB?TimeZone=UTC&sslmode=disable", d.ConnectionString())
+
+	d = Database{Driver: DatabaseDriverSQLite, Name: "company.db"}
+	require.Equal(t, "company.db", d.ConnectionString())
+}
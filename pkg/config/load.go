@@ -0,0 +1,170 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileEnv environment variable naming the optional configuration file
+	FileEnv = "CONFIG_FILE"
+)
+
+type leaf struct {
+	value reflect.Value
+	env   string
+	flag  string
+}
+
+// Load builds the configuration for the named command from defaults, the optional configuration
+// file (-config or CONFIG_FILE, .yaml/.yml/.toml), the .env file (-env-file, defaulting to envFile),
+// environment variables and the command line flags in args. The positional arguments left after
+// the flags are returned alongside. Callers validate the sections they use, the server with
+// Config.Validate, before using the configuration.
+func Load(name string, args []string, envFile string) (*Config, []string, error) {
+	cfg := Default()
+	leaves := collect(reflect.ValueOf(&cfg).Elem())
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := flags.String("config", "", "path to a YAML or TOML configuration file (or "+FileEnv+")")
+	flags.StringVar(&envFile, "env-file", envFile, "path to the .env file")
+
+	// flags win over everything else, so they are only applied once the rest is loaded
+	set := map[string]string{}
+	for _, l := range leaves {
+		if l.flag == "" {
+			continue
+		}
+		name := l.flag
+		flags.Func(name, "overrides "+l.env, func(value string) error {
+			set[name] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if err := godotenv.Load(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("env file %s: %w", envFile, err)
+	}
+
+	if *configFile == "" {
+		*configFile = os.Getenv(FileEnv)
+	}
+	if *configFile != "" {
+		if err := loadFile(*configFile, &cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, l := range leaves {
+		if value, ok := os.LookupEnv(l.env); ok && l.env != "" {
+			if err := assign(l.value, value); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", l.env, err)
+			}
+		}
+	}
+
+	for _, l := range leaves {
+		if value, ok := set[l.flag]; ok {
+			if err := assign(l.value, value); err != nil {
+				return nil, nil, fmt.Errorf("-%s: %w", l.flag, err)
+			}
+		}
+	}
+
+	return &cfg, flags.Args(), nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// collect returns every settable leaf of a configuration struct
+func collect(v reflect.Value) []leaf {
+	var leaves []leaf
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		tag := v.Type().Field(i).Tag
+		if field.Kind() == reflect.Struct {
+			leaves = append(leaves, collect(field)...)
+			continue
+		}
+		leaves = append(leaves, leaf{
+			value: field,
+			env:   tag.Get("env"),
+			flag:  tag.Get("flag"),
+		})
+	}
+	return leaves
+}
+
+// assign parses the string form of a setting into its field
+func assign(field reflect.Value, value string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetInt(int64(i))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
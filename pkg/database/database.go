@@ -0,0 +1,54 @@
+// Package database opens the configured database for database/sql and gorm, whichever driver it uses
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/jackc/pgx/v5/stdlib"  // PostgreSQL driver
+	_ "github.com/mattn/go-sqlite3"     // SQLite driver
+	_ "github.com/microsoft/go-mssqldb" // Microsoft SQL driver
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+
+	"employee-management-system/pkg/config"
+)
+
+// DriverName returns the database/sql driver registered for a configured driver
+func DriverName(driver string) (string, error) {
+	switch driver {
+	case config.DatabaseDriverSQLServer:
+		return "sqlserver", nil
+	case config.DatabaseDriverPostgres:
+		return "pgx", nil
+	case config.DatabaseDriverSQLite:
+		return "sqlite3", nil
+	default:
+		return "", fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// Open returns a database/sql pool for the configured database, without connecting yet
+func Open(cfg config.Database) (*sql.DB, error) {
+	name, err := DriverName(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	return sql.Open(name, cfg.ConnectionString())
+}
+
+// Dialector returns the gorm dialector for the configured database
+func Dialector(cfg config.Database) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case config.DatabaseDriverSQLServer:
+		return sqlserver.Open(cfg.ConnectionString()), nil
+	case config.DatabaseDriverPostgres:
+		return postgres.Open(cfg.ConnectionString()), nil
+	case config.DatabaseDriverSQLite:
+		return sqlite.Open(cfg.ConnectionString()), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,177 @@
+// Package health exposes liveness and readiness endpoints backed by dependency checks
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// StatusOK reported for a passing check and a ready service
+	StatusOK = "ok"
+	// StatusUnavailable reported for a failing check and a service that should not receive traffic
+	StatusUnavailable = "unavailable"
+)
+
+var (
+	// ErrPendingMigrations occurs when the database schema is behind the migrations on disk
+	ErrPendingMigrations = errors.New("database has pending migrations")
+)
+
+type (
+	// Check reports an unhealthy dependency by returning an error
+	Check func(ctx context.Context) error
+
+	// Result of a single check
+	Result struct {
+		Status    string  `json:"status"`
+		LatencyMS float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+	}
+
+	// Report returned by the readiness endpoint
+	Report struct {
+		Status string            `json:"status"`
+		Checks map[string]Result `json:"checks"`
+	}
+
+	// Checker runs the registered checks, each bounded by the timeout
+	Checker struct {
+		timeout time.Duration
+		names   []string
+		checks  map[string]Check
+	}
+)
+
+// New Checker applying timeout to every check
+func New(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  map[string]Check{},
+	}
+}
+
+// Add registers a readiness check under name, replacing any check of the same name
+func (h *Checker) Add(name string, check Check) {
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+		sort.Strings(h.names)
+	}
+	h.checks[name] = check
+}
+
+// Run executes every check concurrently and reports the service unavailable if any failed
+func (h *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(h.names)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, name := range h.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := h.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(name, h.checks[name])
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (h *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	result := Result{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Liveness handler reporting the process is alive, it never touches dependencies
+func (h *Checker) Liveness(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// Readiness handler running every check, answering 503 when any of them fails
+func (h *Checker) Readiness(c *gin.Context) {
+	report := h.Run(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
+
+// Ping checks the database answers
+func Ping(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Migrations checks pending finds no migration left to apply to the database
+func Migrations(pending func(ctx context.Context) (int, error)) Check {
+	return func(ctx context.Context) error {
+		n, err := pending(ctx)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: %d to apply", ErrPendingMigrations, n)
+		}
+		return nil
+	}
+}
+
+// HTTP checks a downstream service answers url with a non-error status
+func HTTP(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("%s answered %s", url, resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func readiness(t *testing.T, h *Checker) (int, Report) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/readyz", h.Readiness)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func Test_Readiness(t *testing.T) {
+	h := New(time.Second)
+	h.Add("database", func(ctx context.Context) error { return nil })
+
+	code, report := readiness(t, h)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, report.Status)
+	require.Equal(t, StatusOK, report.Checks["database"].Status)
+
+	h.Add("migrations", func(ctx context.Context) error { return ErrPendingMigrations })
+
+	code, report = readiness(t, h)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusUnavailable, report.Status)
+	require.Equal(t, StatusOK, report.Checks["database"].Status)
+	require.Equal(t, ErrPendingMigrations.Error(), report.Checks["migrations"].Error)
+}
+
+func Test_Readiness_Timeout(t *testing.T) {
+	h := New(time.Millisecond * 20)
+	h.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("gave up")
+	})
+
+	code, report := readiness(t, h)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "gave up", report.Checks["slow"].Error)
+	require.Less(t, report.Checks["slow"].LatencyMS, float64(1000))
+}
+
+func Test_HTTP(t *testing.T) {
+	status := http.StatusOK
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer downstream.Close()
+
+	check := HTTP(downstream.Client(), downstream.URL)
+	require.NoError(t, check(context.Background()))
+
+	status = http.StatusBadGateway
+	require.Error(t, check(context.Background()))
+}
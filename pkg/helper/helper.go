@@ -16,6 +16,12 @@ const (
 	LogStrKeyLevel = "lev_name"
 	// LogStrKeyMethod log method name value
 	LogStrKeyMethod = "method_name"
+	// LogStrKeyRequestID log request correlation id value
+	LogStrKeyRequestID = "request_id"
+	// LogStrKeyUserID log authenticated user id value
+	LogStrKeyUserID = "user_id"
+	// LogStrKeyOperation log GraphQL operation name value
+	LogStrKeyOperation = "operation_name"
 	// SortOrderASC for ascending sorting
 	SortOrderASC = "ASC"
 	// SortOrderDESC for descending sorting
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type graphqlExtension struct{}
+
+var (
+	_ graphql.HandlerExtension     = graphqlExtension{}
+	_ graphql.OperationInterceptor = graphqlExtension{}
+)
+
+// GraphQL returns a gqlgen extension adding the operation name to the request log fields
+func GraphQL() graphql.HandlerExtension {
+	return graphqlExtension{}
+}
+
+// ExtensionName of the gqlgen extension
+func (graphqlExtension) ExtensionName() string {
+	return "Logging"
+}
+
+// Validate the extension against the schema, there is nothing to check
+func (graphqlExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation names the operation on the context resolvers receive
+func (graphqlExtension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	name := oc.OperationName
+	if name == "" && oc.Operation != nil {
+		name = oc.Operation.Name
+	}
+	if name == "" {
+		name = "anonymous"
+	}
+	return next(WithOperation(ctx, name))
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor is the gRPC counterpart of Middleware for unary calls
+func UnaryServerInterceptor(base zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = grpcRequestID(ctx)
+		resp, err := handler(ctx, req)
+		logCall(ctx, base, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the gRPC counterpart of Middleware for streaming calls
+func StreamServerInterceptor(base zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := grpcRequestID(ss.Context())
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, base, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// grpcRequestID assigns the call a correlation id, reusing a valid x-request-id metadata value, and
+// returns it in the response header
+func grpcRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(strings.ToLower(RequestIDHeader)); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !validRequestID.MatchString(id) {
+		id = newRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(RequestIDHeader), id))
+	return WithRequestID(ctx, id)
+}
+
+func logCall(ctx context.Context, base zerolog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	event := Ctx(ctx, base).Info()
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		event = Ctx(ctx, base).Error()
+	}
+	event.
+		Str("grpc_method", method).
+		Str("grpc_code", code.String()).
+		Dur("latency", time.Since(start)).
+		Msg("call")
+}
+
+// serverStream replaces the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context of the stream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,84 @@
+// Package logging carries request scoped log fields (request ID, user ID, GraphQL operation) on
+// context.Context so every log line written while serving a request can be correlated
+package logging
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/pkg/helper"
+)
+
+const fieldsKey = helper.Key("LoggingFieldsKey")
+
+// fields of the request being served, copied on every change so contexts never share them
+type fields struct {
+	requestID string
+	userID    int
+	operation string
+}
+
+func fromContext(ctx context.Context) fields {
+	if ctx == nil {
+		return fields{}
+	}
+	f, _ := ctx.Value(fieldsKey).(fields)
+	return f
+}
+
+// WithRequestID returns a copy of ctx logging the request correlation id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	f := fromContext(ctx)
+	f.requestID = id
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// WithUserID returns a copy of ctx logging the authenticated user id
+func WithUserID(ctx context.Context, id int) context.Context {
+	f := fromContext(ctx)
+	f.userID = id
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// WithOperation returns a copy of ctx logging the GraphQL operation name
+func WithOperation(ctx context.Context, operation string) context.Context {
+	f := fromContext(ctx)
+	f.operation = operation
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// RequestID returns the correlation id of the request served by ctx, if any
+func RequestID(ctx context.Context) string {
+	return fromContext(ctx).requestID
+}
+
+// Ctx returns the per-request logger: base, which carries the component fields, extended with the
+// request fields found on ctx. Outside of a request it is base unchanged.
+func Ctx(ctx context.Context, base zerolog.Logger) *zerolog.Logger {
+	f := fromContext(ctx)
+	l := base.With()
+	if f.requestID != "" {
+		l = l.Str(helper.LogStrKeyRequestID, f.requestID)
+	}
+	if f.userID != 0 {
+		l = l.Int(helper.LogStrKeyUserID, f.userID)
+	}
+	if f.operation != "" {
+		l = l.Str(helper.LogStrKeyOperation, f.operation)
+	}
+	logger := l.Logger()
+	return &logger
+}
+
+// Method returns the per-request logger for a method of the component base belongs to
+func Method(ctx context.Context, base zerolog.Logger, method string) *zerolog.Logger {
+	logger := Ctx(ctx, base).With().Str(helper.LogStrKeyMethod, method).Logger()
+	return &logger
+}
+
+// Detach returns a background context carrying only the log fields of ctx, for work that outlives
+// the request but should still be correlated with it
+func Detach(ctx context.Context) context.Context {
+	return context.WithValue(context.Background(), fieldsKey, fromContext(ctx))
+}
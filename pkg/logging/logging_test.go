@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/pkg/helper"
+)
+
+func Test_Method(t *testing.T) {
+	var buf bytes.Buffer
+	base := zerolog.New(&buf).With().Str(helper.LogStrKeyLevel, "employee").Logger()
+
+	ctx := WithOperation(WithUserID(WithRequestID(context.Background(), "abc"), 7), "GetEmployees")
+	Method(ctx, base, "GetAllEmployees").Info().Msg("hello")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "employee", line[helper.LogStrKeyLevel])
+	require.Equal(t, "abc", line[helper.LogStrKeyRequestID])
+	require.Equal(t, float64(7), line[helper.LogStrKeyUserID])
+	require.Equal(t, "GetEmployees", line[helper.LogStrKeyOperation])
+	require.Equal(t, "GetAllEmployees", line[helper.LogStrKeyMethod])
+
+	detached := Detach(ctx)
+	require.Equal(t, "abc", RequestID(detached))
+	require.NoError(t, detached.Err())
+}
+
+func Test_Middleware_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var seen string
+	r := gin.New()
+	r.Use(Middleware(zerolog.Nop()))
+	r.GET("/", func(c *gin.Context) { seen = RequestID(c.Request.Context()) })
+
+	for supplied, reused := range map[string]bool{
+		"":                        false,
+		"req-42":                  true,
+		"bad id\nforged log line": false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, supplied)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.NotEmpty(t, seen)
+		require.Equal(t, seen, w.Header().Get(RequestIDHeader))
+		require.Equal(t, reused, seen == supplied, supplied)
+	}
+}
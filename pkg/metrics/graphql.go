@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const (
+	anonymousOperation = "anonymous"
+	statusOK           = "ok"
+	statusError        = "error"
+)
+
+type graphqlExtension struct {
+	m *Metrics
+}
+
+var (
+	_ graphql.HandlerExtension    = graphqlExtension{}
+	_ graphql.ResponseInterceptor = graphqlExtension{}
+	_ graphql.FieldInterceptor    = graphqlExtension{}
+)
+
+// GraphQL returns a gqlgen extension timing every operation and every field with a resolver
+func (m *Metrics) GraphQL() graphql.HandlerExtension {
+	return graphqlExtension{m: m}
+}
+
+// ExtensionName of the gqlgen extension
+func (graphqlExtension) ExtensionName() string {
+	return "Metrics"
+}
+
+// Validate the extension against the schema, there is nothing to check
+func (graphqlExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse counts and times the operation once its response is ready
+func (e graphqlExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	name, kind := oc.OperationName, "unknown"
+	if oc.Operation != nil {
+		kind = string(oc.Operation.Operation)
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+	if name == "" {
+		name = anonymousOperation
+	}
+
+	status := statusOK
+	if resp == nil || len(resp.Errors) > 0 {
+		status = statusError
+	}
+	e.m.operations.WithLabelValues(name, kind, status).Inc()
+	e.m.operationDuration.WithLabelValues(name, kind).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+	return resp
+}
+
+// InterceptField times resolvers, plain struct fields are skipped as they cost nothing
+func (e graphqlExtension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !(fc.IsResolver || fc.IsMethod) {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+	e.m.fieldDuration.WithLabelValues(fc.Object, fc.Field.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		e.m.fieldErrors.WithLabelValues(fc.Object, fc.Field.Name).Inc()
+	}
+	return res, err
+}
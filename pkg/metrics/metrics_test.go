@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"employee-management-system/pkg/gorm_sqlmock"
+)
+
+func Test_HTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New()
+	r := gin.New()
+	r.Use(m.HTTP())
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, float64(2), testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/users/:id", "204")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `employee_system_http_requests_total{method="GET",route="/users/:id",status="204"} 2`)
+}
+
+func Test_Gorm(t *testing.T) {
+	db, mock, err := gorm_sqlmock.New(gorm_sqlmock.Config{
+		Config:     &gorm.Config{},
+		DriverName: "sqlserver",
+		DSN:        "mock",
+	})
+	require.NoError(t, err)
+
+	m := New()
+	require.NoError(t, db.Use(m.Gorm()))
+
+	type user struct{ ID int }
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("SELECT").WillReturnError(errors.New("connection reset"))
+
+	require.NoError(t, db.Find(&[]user{}).Error)
+	require.Error(t, db.Find(&[]user{}).Error)
+
+	require.Equal(t, float64(1), testutil.ToFloat64(m.queries.WithLabelValues("query", "users", statusOK)))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.queries.WithLabelValues("query", "users", statusError)))
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
+)
+
+const (
+	// APIKeyHeader request header carrying a service account API key
+	APIKeyHeader = "X-API-Key"
+	// apiKeyTouchInterval limits how often last-used timestamps are written for a busy key
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	// ErrInvalidAPIKey occurs when an API key is unknown, revoked or expired
+	ErrInvalidAPIKey = errors.New("api key is invalid")
+)
+
+// APIKeyAuthorization returns the service account User owning the API key supplied in the X-API-Key header
+func (m *Middleware) APIKeyAuthorization(c *gin.Context) (*model.User, error) {
+	secret := model.APIKeySecret(c.GetHeader(APIKeyHeader))
+	if secret.Prefix() == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// the key is unique across tenants, its service account tells the tenant
+	key, err := m.apiKeyStorage.GetAPIKeyByHash(tenant.Unscoped(c), secret.Hash())
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := m.jwt.TimeFunc()
+	if !key.Active(now) || key.User.ID == 0 || key.User.TenantID != key.TenantID {
+		logging.Method(c, m.logger, "APIKeyAuthorization").Warn().Msgf("APIKeyAuthorization: rejected key %s", key.Prefix)
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		// the request must not wait on, nor fail because of, bookkeeping
+		go func(ctx context.Context, id int) {
+			if err := m.apiKeyStorage.UpdateAPIKeyLastUsed(ctx, id, now); err != nil {
+				logging.Method(ctx, m.logger, "APIKeyAuthorization").Err(err).Msgf("APIKeyAuthorization: last used update failed: %v", err)
+			}
+		}(tenant.WithID(logging.Detach(c), key.TenantID), key.ID)
+	}
+
+	return m.evalKindForRelationship(c, &key.User)
+}
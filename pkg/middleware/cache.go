@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"employee-management-system/model"
+)
+
+const (
+	// userCacheTTL bounds how long a role change or lock made on another instance, which can not
+	// invalidate this one's cache, goes unnoticed
+	userCacheTTL = 30 * time.Second
+	// userCacheSize bounds the users kept, the soonest to expire make room for new ones
+	userCacheSize = 10000
+)
+
+type (
+	// userCache keeps recently authorized users in memory to save a database round trip per request
+	userCache struct {
+		mu    sync.RWMutex
+		ttl   time.Duration
+		size  int
+		users map[string]cachedUser
+	}
+
+	cachedUser struct {
+		user    *model.User
+		expires time.Time
+	}
+)
+
+func newUserCache(ttl time.Duration) *userCache {
+	if ttl <= 0 || ttl > userCacheTTL {
+		ttl = userCacheTTL
+	}
+	return &userCache{
+		ttl:   ttl,
+		size:  userCacheSize,
+		users: map[string]cachedUser{},
+	}
+}
+
+func (u *userCache) user(id string) *model.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	entry, ok := u.users[id]
+	if !ok || time.Now().After(entry.expires) {
+		return nil
+	}
+	return entry.user
+}
+
+func (u *userCache) set(id string, user *model.User) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	if _, ok := u.users[id]; !ok && len(u.users) >= u.size {
+		u.evict(now)
+	}
+	u.users[id] = cachedUser{
+		user:    user,
+		expires: now.Add(u.ttl),
+	}
+}
+
+// forget drops the user, the next request reads it from the database again
+func (u *userCache) forget(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.users, id)
+}
+
+// evict drops the expired users, or the one expiring soonest when none has
+func (u *userCache) evict(now time.Time) {
+	var (
+		soonest string
+		at      time.Time
+	)
+	for id, entry := range u.users {
+		if now.After(entry.expires) {
+			delete(u.users, id)
+			continue
+		}
+		if soonest == "" || entry.expires.Before(at) {
+			soonest, at = id, entry.expires
+		}
+	}
+	if len(u.users) >= u.size {
+		delete(u.users, soonest)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+)
+
+func Test_UserCache(t *testing.T) {
+	// entries never outlive userCacheTTL, whatever the access token expiry
+	cache := newUserCache(time.Hour)
+	require.Equal(t, userCacheTTL, cache.ttl)
+
+	cache.size = 3
+	for i := 1; i <= 5; i++ {
+		cache.set(strconv.Itoa(i), &model.User{ID: i})
+	}
+	require.Len(t, cache.users, 3)
+	require.Nil(t, cache.user("1"))
+	require.Equal(t, 5, cache.user("5").ID)
+
+	// expired users make room first
+	cache.users["4"] = cachedUser{user: &model.User{ID: 4}, expires: time.Now().Add(-time.Second)}
+	cache.set("6", &model.User{ID: 6})
+	require.Len(t, cache.users, 3)
+	require.NotContains(t, cache.users, "4")
+	require.Equal(t, 3, cache.user("3").ID)
+
+	cache.forget("6")
+	require.Nil(t, cache.user("6"))
+}
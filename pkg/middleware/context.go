@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
+)
+
+const (
+	// GinContextKey context key holding the *gin.Context of the request
+	GinContextKey helper.Key = "gin_context_in_context"
+	// UserContextKey context key holding the authenticated *model.User of the request
+	UserContextKey helper.Key = "request_user_in_context"
+	// SessionContextKey context key holding the session id of the request's access token
+	SessionContextKey helper.Key = "request_session_in_context"
+)
+
+// GinContextToContext stores the gin context on the request context so GraphQL resolvers can
+// reach cookies and headers
+func (m *Middleware) GinContextToContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), GinContextKey, c)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// GinContextFromContext retrieves the gin context stored by GinContextToContext
+func GinContextFromContext(ctx context.Context) (*gin.Context, error) {
+	c, ok := ctx.Value(GinContextKey).(*gin.Context)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	return c, nil
+}
+
+// Authenticate resolves the caller from an X-API-Key header or a bearer token and stores it on the
+// request context. Requests without credentials continue anonymously so resolvers such as login
+// stay reachable, while invalid credentials are rejected outright.
+func (m *Middleware) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, err := m.authenticate(c)
+		if err != nil {
+			c.Header("WWW-Authenticate", "JWT realm="+realm)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"code":    http.StatusUnauthorized,
+				"message": ErrUnauthorized.Error(),
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AuthenticateHeader authenticates a call received over another transport, such as gRPC, whose
+// credentials are given as HTTP headers. It returns ctx carrying the caller as Authenticate does, or
+// ctx unchanged when header holds no credentials.
+func (m *Middleware) AuthenticateHeader(ctx context.Context, header http.Header) (context.Context, error) {
+	// the gin context only reads the credentials, no response is ever written to it
+	c := gin.CreateTestContextOnly(nil, m.engine)
+	c.Request = (&http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/"}, Header: header}).WithContext(ctx)
+	return m.authenticate(c)
+}
+
+// authenticate returns the request context carrying the caller of c
+func (m *Middleware) authenticate(c *gin.Context) (context.Context, error) {
+	var (
+		user *model.User
+		err  error
+	)
+	switch {
+	case c.GetHeader(APIKeyHeader) != "":
+		user, err = m.APIKeyAuthorization(c)
+	case strings.HasPrefix(c.GetHeader("Authorization"), m.jwt.TokenHeadName+" "):
+		user, err = m.JwtAuthorization(c)
+	default:
+		return c.Request.Context(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(RequestUserIDInContext, user.ID)
+	ctx := tenant.WithID(ContextWithUser(c.Request.Context(), user), user.TenantID)
+	return ContextWithSessionID(ctx, c.GetInt(RequestSessionIDInContext)), nil
+}
+
+// ContextWithUser returns a copy of ctx carrying the authenticated user, whose id is also logged
+func ContextWithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(logging.WithUserID(ctx, user.ID), UserContextKey, user)
+}
+
+// UserFromContext returns the authenticated user stored by Authenticate
+func UserFromContext(ctx context.Context) (*model.User, error) {
+	user, ok := ctx.Value(UserContextKey).(*model.User)
+	if !ok || user == nil {
+		return nil, ErrUnauthorized
+	}
+	return user, nil
+}
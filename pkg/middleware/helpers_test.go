@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/storage"
+)
+
+func newTestMiddleware(t *testing.T) *Middleware {
+	cfg := config.Default()
+	cfg.JWT.SigningSecret = "secret"
+
+	jwt, err := jwtMiddleware(cfg.JWT, nil)
+	require.NoError(t, err)
+	return &Middleware{
+		logger:         zerolog.Nop(),
+		config:         &cfg,
+		jwt:            jwt,
+		sessionStorage: newFakeSessions(),
+		cache:          newUserCache(time.Minute),
+		engine:         newEngine(),
+	}
+}
+
+func newTestContext() *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/query", nil)
+	return c
+}
+
+// fakeSessions is an in memory storage.SessionDatabase
+type fakeSessions struct {
+	mu       sync.Mutex
+	sessions map[int]model.Session
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{sessions: map[int]model.Session{}}
+}
+
+func (f *fakeSessions) AddSession(_ context.Context, session model.Session) (model.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	session.ID = len(f.sessions) + 1
+	session.CreatedAt = time.Now()
+	f.sessions[session.ID] = session
+	return session, nil
+}
+
+func (f *fakeSessions) GetSessionByID(_ context.Context, id int) (model.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	session, ok := f.sessions[id]
+	if !ok {
+		return session, storage.ErrRecordNotFound
+	}
+	return session, nil
+}
+
+func (f *fakeSessions) GetActiveSessionsByUserID(_ context.Context, userID int, at time.Time) ([]*model.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sessions []*model.Session
+	for _, session := range f.sessions {
+		if session.UserID == userID && session.Active(at) {
+			session := session
+			sessions = append(sessions, &session)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *fakeSessions) RevokeSessionByID(_ context.Context, id int, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	session := f.sessions[id]
+	session.RevokedAt = &at
+	f.sessions[id] = session
+	return nil
+}
+
+func (f *fakeSessions) RevokeAllSessionsByUserID(_ context.Context, userID int, at time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var count int64
+	for id, session := range f.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			f.sessions[id] = session
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeSessions) UpdateSessionLastSeen(_ context.Context, id int, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	session := f.sessions[id]
+	session.LastSeenAt = at
+	f.sessions[id] = session
+	return nil
+}
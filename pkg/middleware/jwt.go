@@ -5,18 +5,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
 	ginJwt "github.com/appleboy/gin-jwt/v2"
-	jwtGo "github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	jwtGo "github.com/golang-jwt/jwt/v4"
 	"github.com/rs/zerolog"
 
 	graphModel "employee-management-system/graph/model"
 	"employee-management-system/model"
-	"employee-management-system/pkg/environment"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
 )
 
 type (
@@ -30,14 +31,21 @@ type (
 )
 
 var (
-	identityKey   = "id"
-	realm         = "c-o-m-p-a-n-y"
-	maxRefresh    = jwtRefreshTokenExpiry()
-	accessTimeout = jwtAccessTokenExpiry()
+	identityKey = "id"
+	realm       = "c-o-m-p-a-n-y"
 	// claimsID id key for middleware claims
 	claimsID        = "id"
 	claimsExpiry    = "exp"
 	claimsCreatedAt = "orig_iat"
+	// claimsType distinguishes access, refresh and two-factor challenge tokens
+	claimsType         = "typ"
+	tokenTypeAccess    = "access"
+	tokenTypeRefresh   = "refresh"
+	tokenTypeChallenge = "2fa_challenge"
+	// claimsSessionID id of the session the token pair belongs to
+	claimsSessionID = "sid"
+	// claimsTenantID id of the tenant of the user, every query of the request is scoped to it
+	claimsTenantID = "tid"
 	// ErrFailedAuthentication incorrect email or password
 	ErrFailedAuthentication = errors.New("incorrect email or password")
 	// ErrAccountSuspended user account is suspended
@@ -56,8 +64,8 @@ var (
 
 // JwtAuthenticator authenticates user by username and password
 func (m *Middleware) JwtAuthenticator(c *gin.Context, u, p string) (*graphModel.AuthResponse, error) {
-	// attempt login
-	user, err := m.userStorage.Authenticate(c, u, p)
+	// attempt login, user names are unique across tenants so the user tells the tenant
+	user, err := m.userStorage.Authenticate(tenant.Unscoped(c), u, p)
 	if err != nil {
 		return nil, ErrFailedAuthentication
 	}
@@ -68,13 +76,23 @@ func (m *Middleware) JwtAuthenticator(c *gin.Context, u, p string) (*graphModel.
 		return nil, err
 	}
 
+	// a second factor is needed before any token granting access is issued
+	if user.TwoFactorEnabled() || m.twoFactorRequired(user) {
+		return m.twoFactorChallenge(user)
+	}
+
+	return m.issueTokens(c, user)
+}
+
+// issueTokens generates the token pair for an authenticated user and sets the auth cookies
+func (m *Middleware) issueTokens(c *gin.Context, user *model.User) (*graphModel.AuthResponse, error) {
 	tokens, err := m.GenerateTokens(c, user)
 	if err != nil {
 		return nil, ginJwt.ErrFailedTokenCreation
 	}
 
 	if m.jwt.SendCookie {
-		maxage := int(time.Now().Add(accessTimeout).Unix() - time.Now().Unix())
+		maxage := int(time.Now().Add(m.jwt.Timeout).Unix() - time.Now().Unix())
 		c.SetCookie(
 			m.jwt.CookieName,
 			tokens.AccessToken,
@@ -89,7 +107,7 @@ func (m *Middleware) JwtAuthenticator(c *gin.Context, u, p string) (*graphModel.
 	return &graphModel.AuthResponse{
 		Token:              &tokens.AccessToken,
 		Refresh:            &tokens.RefreshToken,
-		User:               user,
+		User:               toGraphUser(user),
 		AccessTokenExpiry:  &tokens.AccessTokenExpiry,
 		RefreshTokenExpiry: &tokens.RefreshTokenExpiry,
 	}, nil
@@ -109,16 +127,35 @@ func (m *Middleware) GenerateTokens(c *gin.Context, user *model.User) (*Tokens,
 			refreshClaims[key] = value
 		}
 	}
-	accessExpire := time.Now().Add(accessTimeout)
-	refreshExpire := time.Now().Add(maxRefresh)
+	accessExpire := time.Now().Add(m.jwt.Timeout)
+	refreshExpire := time.Now().Add(m.jwt.MaxRefresh)
+
+	// every token pair belongs to a session so it can be listed and revoked
+	session, err := m.sessionStorage.AddSession(tenant.WithID(c, user.TenantID), model.Session{
+		TenantID:   user.TenantID,
+		UserID:     user.ID,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+		LastSeenAt: m.jwt.TimeFunc(),
+		ExpiresAt:  refreshExpire,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	accessClaims[claimsID] = user.ID
+	accessClaims[claimsSessionID] = session.ID
+	accessClaims[claimsTenantID] = user.TenantID
 	accessClaims[claimsExpiry] = accessExpire.Unix()
 	accessClaims[claimsCreatedAt] = m.jwt.TimeFunc().Unix()
+	accessClaims[claimsType] = tokenTypeAccess
 
 	refreshClaims[claimsID] = user.ID
+	refreshClaims[claimsSessionID] = session.ID
+	refreshClaims[claimsTenantID] = user.TenantID
 	refreshClaims[claimsExpiry] = refreshExpire.Unix()
 	refreshClaims[claimsCreatedAt] = m.jwt.TimeFunc().Unix()
+	refreshClaims[claimsType] = tokenTypeRefresh
 
 	accessTokenString, err := m.signedString(accessToken)
 	if err != nil {
@@ -133,7 +170,7 @@ func (m *Middleware) GenerateTokens(c *gin.Context, user *model.User) (*Tokens,
 	c.SetCookie(
 		fmt.Sprintf("%v", user.ID),
 		refreshTokenString,
-		int(time.Now().Add(maxRefresh).Unix()-time.Now().Unix()),
+		int(time.Now().Add(m.jwt.MaxRefresh).Unix()-time.Now().Unix()),
 		"/",
 		m.jwt.CookieDomain,
 		m.jwt.SecureCookie,
@@ -156,16 +193,39 @@ func (m *Middleware) JwtAuthorization(c *gin.Context) (*model.User, error) {
 	if err != nil {
 		return nil, err
 	}
+	if claims[claimsType] != tokenTypeAccess {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := claimInt(claims[claimsID])
+	if err != nil {
+		return nil, err
+	}
+	tenantID, err := claimTenant(claims)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := m.checkSession(c, claims, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(RequestSessionIDInContext, sessionID)
 
-	userID := claims[claimsID].(string)
-	if user = m.cache.user(userID); user == nil {
-		// get user by ID
-		dbUser, err := m.userStorage.GetUserByID(c, userID)
+	if user = m.cache.user(strconv.Itoa(userID)); user == nil {
+		// get user by ID, within the tenant of the token
+		dbUser, err := m.userStorage.GetUserByID(tenant.WithID(c, tenantID), userID)
 		if err != nil {
 			return nil, err
 		}
 
-		return m.evalKindForRelationship(c, &dbUser)
+		user, err = m.evalKindForRelationship(c, &dbUser)
+		if err != nil {
+			return nil, err
+		}
+		m.cache.set(strconv.Itoa(userID), user)
+	}
+	if user.TenantID != tenantID {
+		return nil, ErrInvalidToken
 	}
 
 	return user, nil
@@ -177,7 +237,10 @@ func (m *Middleware) GetGinJWTMiddleware() *ginJwt.GinJWTMiddleware {
 }
 
 func (m *Middleware) evalKindForRelationship(ctx context.Context, user *model.User) (*model.User, error) {
-
+	if user.Locked() {
+		logging.Method(ctx, m.logger, "evalKindForRelationship").Warn().Msgf("evalKindForRelationship: user %d is locked", user.ID)
+		return nil, ErrAccountSuspended
+	}
 	return user, nil
 }
 
@@ -197,55 +260,69 @@ func (m *Middleware) LogoutHandler(c *gin.Context) {
 	}
 }
 
+// JWKSHandler publishes the public verification keys as a JSON Web Key Set so other services
+// can verify our tokens without sharing a secret. The set is empty when tokens are HMAC signed.
+func (m *Middleware) JWKSHandler(c *gin.Context) {
+	set := JWKS{Keys: []JWK{}}
+	if m.keys != nil {
+		var err error
+		if set, err = m.keys.JWKS(); err != nil {
+			logging.Method(c, m.logger, "JWKSHandler").Err(err).Msgf("JWKSHandler error: %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
+
 func (m *Middleware) signedString(token *jwtGo.Token) (string, error) {
-	var tokenString string
-	var err error
-	if m.usingPublicKeyAlgo() {
-		tokenString, err = token.SignedString(m.pKey)
-	} else {
-		tokenString, err = token.SignedString(m.jwt.Key)
-	}
-	return tokenString, err
+	if m.keys != nil {
+		return m.keys.Sign(token)
+	}
+	return token.SignedString(m.jwt.Key)
 }
 
-func (m *Middleware) usingPublicKeyAlgo() bool {
-	switch m.jwt.SigningAlgorithm {
-	case "RS256", "RS512", "RS384":
-		return true
+func claimInt(claim interface{}) (int, error) {
+	switch v := claim.(type) {
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		return strconv.Atoi(v)
 	}
-	return false
+	return 0, ErrInvalidToken
 }
 
-func jwtAccessTokenExpiry() time.Duration {
-	env, _ := environment.New()
-	ttl, err := strconv.Atoi(env.Get("JWT_ACCESS_TOKEN_EXPIRY"))
-	if err != nil {
-		return time.Minute * 15
+// claimTenant returns the tenant of the claims. Tokens issued before tenants were introduced carry
+// none and belong to the default tenant.
+func claimTenant(claims map[string]interface{}) (int, error) {
+	claim, ok := claims[claimsTenantID]
+	if !ok {
+		return tenant.DefaultID, nil
 	}
-	return time.Minute * time.Duration(ttl)
+	return claimInt(claim)
 }
 
-func jwtRefreshTokenExpiry() time.Duration {
-	env, _ := environment.New()
-	ttl, err := strconv.Atoi(env.Get("JWT_REFRESH_TOKEN_EXPIRY"))
-	if err != nil {
-		return time.Hour * 24
+func toGraphUser(user *model.User) *graphModel.User {
+	u := &graphModel.User{
+		ID: strconv.Itoa(user.ID),
+	}
+	if user.UserName != nil {
+		u.UserName = *user.UserName
 	}
-	return time.Minute * time.Duration(ttl)
+	createdAt := user.CreatedAt.String()
+	updatedAt := user.UpdatedAt.String()
+	u.CreatedAt = &createdAt
+	u.UpdatedAt = &updatedAt
+	return u
 }
 
 // ValidateRefreshToken validates refresh token
 func (m *Middleware) ValidateRefreshToken(z zerolog.Logger, c *gin.Context, token string) (*string, error) {
-	tokenGotten, err := jwtGo.Parse(token, func(token *jwtGo.Token) (interface{}, error) {
-		//Make sure that the token method conform to "SigningMethodHMAC"
-		if _, ok := token.Method.(*jwtGo.SigningMethodHMAC); !ok {
-			z.Error().Msgf("RefreshToken unexpected signing method: (%v)", token.Header["alg"])
-
-			return nil, ErrUnexpectedSigningMethod
-		}
-		env, _ := environment.New()
-		return []byte(env.Get("SIGNING_SECRET_KEY")), nil
-	})
+	tokenGotten, err := m.parseToken(token)
 
 	//any error may be due to token expiration
 	if err != nil {
@@ -260,9 +337,21 @@ func (m *Middleware) ValidateRefreshToken(z zerolog.Logger, c *gin.Context, toke
 	}
 
 	claims, ok := tokenGotten.Claims.(jwtGo.MapClaims)
-	claimsUUID := claims[claimsID].(string)
+	if claims[claimsType] != tokenTypeRefresh {
+		return nil, ErrInvalidToken
+	}
+	userID, err := claimInt(claims[claimsID])
+	if err != nil {
+		z.Err(err).Msgf("RefreshToken: Invalid user (%v)", err)
+		return nil, err
+	}
+	if _, err := m.checkSession(c, claims, userID); err != nil {
+		z.Err(err).Msgf("RefreshToken error: %v", err)
+		return nil, err
+	}
+	claimsUserID := strconv.Itoa(userID)
 	//get the last refresh token for this user
-	refreshTokenCookie, err := c.Cookie(claimsUUID)
+	refreshTokenCookie, err := c.Cookie(claimsUserID)
 	//error may be due to cookie expiration OR a new refresh token has been generated
 	if err != nil || refreshTokenCookie != token {
 		z.Err(err).Msgf("RefreshToken:Cookie error: %v", err)
@@ -270,15 +359,24 @@ func (m *Middleware) ValidateRefreshToken(z zerolog.Logger, c *gin.Context, toke
 	}
 
 	if ok && tokenGotten.Valid {
-		//convert the interface to uuid.UUID
-		parsedUUID, err := uuid.Parse(claimsUUID)
-		if err != nil {
-			z.Err(err).Msgf("RefreshToken: Invalid user (%v)", err)
-			return nil, err
-		}
-
-		return &parsedUUID, nil
+		return &claimsUserID, nil
 	}
 
 	return nil, ErrInvalidToken
 }
+
+// parseToken verifies a token string with the configured secret or key set
+func (m *Middleware) parseToken(token string) (*jwtGo.Token, error) {
+	if m.keys != nil {
+		return jwtGo.Parse(token, m.keys.KeyFunc)
+	}
+	return jwtGo.Parse(token, func(token *jwtGo.Token) (interface{}, error) {
+		//Make sure that the token method conform to "SigningMethodHMAC"
+		if _, ok := token.Method.(*jwtGo.SigningMethodHMAC); !ok {
+			m.logger.Error().Msgf("RefreshToken unexpected signing method: (%v)", token.Header["alg"])
+
+			return nil, ErrUnexpectedSigningMethod
+		}
+		return m.jwt.Key, nil
+	})
+}
@@ -0,0 +1,368 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	jwtGo "github.com/golang-jwt/jwt/v4"
+
+	"employee-management-system/pkg/config"
+)
+
+var (
+	// ErrUnsupportedKey occurs when a PEM file holds a key type we can not sign or verify with
+	ErrUnsupportedKey = errors.New("unsupported key type")
+	// ErrInvalidKeyPEM occurs when a key file does not contain a PEM block
+	ErrInvalidKeyPEM = errors.New("key file is not PEM encoded")
+	// ErrKeyAlgorithmMismatch occurs when a key can not be used with the configured signing algorithm
+	ErrKeyAlgorithmMismatch = errors.New("key does not match signing algorithm")
+	// ErrUnknownKeyID occurs when a token references a key id we do not hold
+	ErrUnknownKeyID = errors.New("unknown key id")
+)
+
+type (
+	// KeySet holds the private key used to sign new tokens together with every
+	// public key accepted when verifying them, indexed by key id (kid).
+	// Keeping previous keys in the set allows signing keys to be rotated without
+	// invalidating tokens that are still in circulation.
+	KeySet struct {
+		mu         sync.RWMutex
+		algorithm  string
+		signingKID string
+		signingKey crypto.Signer
+		keys       map[string]crypto.PublicKey
+	}
+
+	// JWK is the JSON Web Key representation of a public key (RFC 7517)
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg,omitempty"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	// JWKS is a JSON Web Key Set as served from /.well-known/jwks.json
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+// NewKeySet creates an empty KeySet for the supplied asymmetric signing algorithm
+func NewKeySet(algorithm string) (*KeySet, error) {
+	if !config.IsAsymmetricAlgorithm(algorithm) {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedSigningMethod, algorithm)
+	}
+	return &KeySet{
+		algorithm: algorithm,
+		keys:      map[string]crypto.PublicKey{},
+	}, nil
+}
+
+// Algorithm returns the signing algorithm of this KeySet
+func (k *KeySet) Algorithm() string {
+	return k.algorithm
+}
+
+// SigningKeyID returns the kid stamped on newly signed tokens
+func (k *KeySet) SigningKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.signingKID
+}
+
+// LoadSigningKeyFile reads a PEM encoded private key and makes it the active signing key.
+// When kid is empty the RFC 7638 thumbprint of the public key is used instead.
+func (k *KeySet) LoadSigningKeyFile(kid, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return k.SetSigningKey(kid, data)
+}
+
+// SetSigningKey parses a PEM encoded private key and makes it the active signing key
+func (k *KeySet) SetSigningKey(kid string, pemData []byte) error {
+	signer, err := parsePrivateKey(pemData)
+	if err != nil {
+		return err
+	}
+	if err := checkKeyAlgorithm(k.algorithm, signer.Public()); err != nil {
+		return err
+	}
+	if kid == "" {
+		if kid, err = thumbprint(signer.Public()); err != nil {
+			return err
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.signingKID = kid
+	k.signingKey = signer
+	k.keys[kid] = signer.Public()
+	return nil
+}
+
+// LoadVerificationKeyFile reads a PEM encoded public key, certificate or private key and
+// accepts it for verification only, as is the case for a key that has just been rotated out
+func (k *KeySet) LoadVerificationKeyFile(kid, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return k.AddVerificationKey(kid, data)
+}
+
+// AddVerificationKey parses a PEM encoded public key, certificate or private key and
+// accepts it for verification only
+func (k *KeySet) AddVerificationKey(kid string, pemData []byte) error {
+	pub, err := parsePublicKey(pemData)
+	if err != nil {
+		return err
+	}
+	if err := checkKeyAlgorithm(k.algorithm, pub); err != nil {
+		return err
+	}
+	if kid == "" {
+		if kid, err = thumbprint(pub); err != nil {
+			return err
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[kid] = pub
+	return nil
+}
+
+// Sign stamps the active key id on the token header and signs it
+func (k *KeySet) Sign(token *jwtGo.Token) (string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.signingKey == nil {
+		return "", ErrUnsupportedKey
+	}
+	token.Header["kid"] = k.signingKID
+	return token.SignedString(k.signingKey)
+}
+
+// KeyFunc resolves the verification key of a token from its kid header
+func (k *KeySet) KeyFunc(token *jwtGo.Token) (interface{}, error) {
+	if token.Method.Alg() != k.algorithm {
+		return nil, ErrUnexpectedSigningMethod
+	}
+	kid, _ := token.Header["kid"].(string)
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return key, nil
+}
+
+// JWKS returns every verification key of this KeySet in JSON Web Key Set form, ordered by kid
+func (k *KeySet) JWKS() (JWKS, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for kid, pub := range k.keys {
+		jwk, err := toJWK(pub)
+		if err != nil {
+			return JWKS{}, err
+		}
+		jwk.Kid = kid
+		jwk.Use = "sig"
+		jwk.Alg = k.algorithm
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set, nil
+}
+
+// ParseKeyList parses a comma separated list of kid=path pairs
+func ParseKeyList(list string) (map[string]string, error) {
+	keys := map[string]string{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(kid) == "" || strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("invalid verification key entry %q, expected kid=path", entry)
+		}
+		keys[strings.TrimSpace(kid)] = strings.TrimSpace(path)
+	}
+	return keys, nil
+}
+
+func parsePrivateKey(pemData []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, ErrInvalidKeyPEM
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+func parsePublicKey(pemData []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, ErrInvalidKeyPEM
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	signer, err := parsePrivateKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Public(), nil
+}
+
+func checkKeyAlgorithm(alg string, pub crypto.PublicKey) error {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS") {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		switch {
+		case alg == "ES256" && pub.Curve == elliptic.P256(),
+			alg == "ES384" && pub.Curve == elliptic.P384(),
+			alg == "ES512" && pub.Curve == elliptic.P521():
+			return nil
+		}
+	case ed25519.PublicKey:
+		if alg == "EdDSA" {
+			return nil
+		}
+	default:
+		return ErrUnsupportedKey
+	}
+	return fmt.Errorf("%w: %T for %s", ErrKeyAlgorithmMismatch, pub, alg)
+}
+
+func toJWK(pub crypto.PublicKey) (JWK, error) {
+	enc := base64.RawURLEncoding
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   enc.EncodeToString(pub.N.Bytes()),
+			E:   enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC",
+			Crv: pub.Curve.Params().Name,
+			X:   enc.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+			Y:   enc.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   enc.EncodeToString(pub),
+		}, nil
+	}
+	return JWK{}, ErrUnsupportedKey
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint of a public key
+func thumbprint(pub crypto.PublicKey) (string, error) {
+	jwk, err := toJWK(pub)
+	if err != nil {
+		return "", err
+	}
+
+	// members must be in lexicographic order with no whitespace
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jwtGo "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func Test_KeySet_SignAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for alg, key := range map[string]interface{}{"RS256": rsaKey, "ES256": ecKey, "EdDSA": edKey} {
+		keys, err := NewKeySet(alg)
+		require.NoError(t, err)
+		require.NoError(t, keys.LoadSigningKeyFile("current", writePrivateKey(t, key)))
+
+		signed, err := keys.Sign(jwtGo.NewWithClaims(jwtGo.GetSigningMethod(alg), jwtGo.MapClaims{claimsID: 7}))
+		require.NoError(t, err, alg)
+
+		token, err := jwtGo.Parse(signed, keys.KeyFunc)
+		require.NoError(t, err, alg)
+		require.Equal(t, "current", token.Header["kid"])
+		require.Equal(t, float64(7), token.Claims.(jwtGo.MapClaims)[claimsID])
+	}
+}
+
+func Test_KeySet_Rotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	before, err := NewKeySet("ES256")
+	require.NoError(t, err)
+	require.NoError(t, before.LoadSigningKeyFile("2023-01", writePrivateKey(t, oldKey)))
+	oldToken, err := before.Sign(jwtGo.New(jwtGo.SigningMethodES256))
+	require.NoError(t, err)
+
+	after, err := NewKeySet("ES256")
+	require.NoError(t, err)
+	require.NoError(t, after.LoadSigningKeyFile("2023-02", writePrivateKey(t, newKey)))
+
+	_, err = jwtGo.Parse(oldToken, after.KeyFunc)
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+
+	require.NoError(t, after.LoadVerificationKeyFile("2023-01", writePrivateKey(t, oldKey)))
+	_, err = jwtGo.Parse(oldToken, after.KeyFunc)
+	require.NoError(t, err)
+
+	set, err := after.JWKS()
+	require.NoError(t, err)
+	require.Len(t, set.Keys, 2)
+	require.Equal(t, "2023-01", set.Keys[0].Kid)
+	require.Equal(t, "EC", set.Keys[0].Kty)
+	require.Equal(t, "P-256", set.Keys[0].Crv)
+}
+
+func Test_KeySet_AlgorithmMismatch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys, err := NewKeySet("ES256")
+	require.NoError(t, err)
+	require.ErrorIs(t, keys.LoadSigningKeyFile("", writePrivateKey(t, rsaKey)), ErrKeyAlgorithmMismatch)
+
+	_, err = NewKeySet("HS256")
+	require.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+}
+
+func Test_KeySet_DefaultKeyIDIsThumbprint(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys, err := NewKeySet("EdDSA")
+	require.NoError(t, err)
+	require.NoError(t, keys.LoadSigningKeyFile("", writePrivateKey(t, edKey)))
+
+	kid, err := thumbprint(edKey.Public())
+	require.NoError(t, err)
+	require.Equal(t, kid, keys.SigningKeyID())
+}
+
+func Test_ParseKeyList(t *testing.T) {
+	keys, err := ParseKeyList(" a=/keys/a.pem, b=/keys/b.pem ,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "/keys/a.pem", "b": "/keys/b.pem"}, keys)
+
+	_, err = ParseKeyList("/keys/a.pem")
+	require.Error(t, err)
+}
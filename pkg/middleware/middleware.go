@@ -1,13 +1,16 @@
 package middleware
 
 import (
-	"crypto/rsa"
+	"fmt"
+	"strconv"
+	"strings"
 
 	ginJwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"employee-management-system/model"
-	"employee-management-system/pkg/environment"
+	"employee-management-system/pkg/config"
 	"employee-management-system/pkg/helper"
 	"employee-management-system/storage"
 )
@@ -24,34 +27,96 @@ type (
 
 	// Middleware object
 	Middleware struct {
-		logger          zerolog.Logger
-		env             environment.Env
-		employeeStorage storage.EmployeeDatabase
-		userStorage     storage.UserDatabase
-		jwt             *ginJwt.GinJWTMiddleware
-		pKey            *rsa.PrivateKey
+		logger           zerolog.Logger
+		config           *config.Config
+		employeeStorage  storage.EmployeeDatabase
+		userStorage      storage.UserDatabase
+		apiKeyStorage    storage.APIKeyDatabase
+		twoFactorStorage storage.TwoFactorDatabase
+		sessionStorage   storage.SessionDatabase
+		twoFactorKinds   map[model.Kind]bool
+		jwt              *ginJwt.GinJWTMiddleware
+		keys             *KeySet
+		cache            *userCache
+		// engine creates the gin contexts of AuthenticateHeader
+		engine *gin.Engine
 	}
 )
 
-// NewMiddleware new instance of our custom ginJwt middleware
-func NewMiddleware(z zerolog.Logger, env environment.Env, s *storage.Storage) *Middleware {
-	mWare, _ := jwtMiddleware(env.Get("SIGNING_SECRET_KEY"))
+// NewMiddleware new instance of our custom ginJwt middleware.
+// Tokens are signed with the shared secret unless an asymmetric algorithm is configured, in which
+// case the private key file signs new tokens and the verification keys list previous keys still
+// accepted during rotation.
+func NewMiddleware(z zerolog.Logger, cfg *config.Config, s *storage.Storage) (*Middleware, error) {
 	l := z.With().Str(helper.LogStrKeyModule, packageName).Logger()
 
+	keys, err := loadKeySet(cfg.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	mWare, err := jwtMiddleware(cfg.JWT, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactorKinds := map[model.Kind]bool{}
+	for _, role := range cfg.TwoFactor.RequiredRoles {
+		kind, ok := model.KindFromString(role)
+		if !ok {
+			return nil, fmt.Errorf("unknown two-factor role %q", role)
+		}
+		twoFactorKinds[kind] = true
+	}
+
 	return &Middleware{
-		logger:          l,
-		env:             env,
-		userStorage:     *storage.NewUser(s),
-		employeeStorage: *storage.NewEmployee(s),
-		jwt:             mWare,
+		logger:           l,
+		config:           cfg,
+		userStorage:      *storage.NewUser(s),
+		apiKeyStorage:    *storage.NewAPIKey(s),
+		twoFactorStorage: *storage.NewTwoFactor(s),
+		sessionStorage:   *storage.NewSession(s),
+		twoFactorKinds:   twoFactorKinds,
+		employeeStorage:  *storage.NewEmployee(s),
+		jwt:              mWare,
+		keys:             keys,
+		cache:            newUserCache(cfg.JWT.AccessTokenExpiry.Duration()),
+		engine:           newEngine(),
+	}, nil
+}
+
+func loadKeySet(cfg config.JWT) (*KeySet, error) {
+	if !config.IsAsymmetricAlgorithm(cfg.SigningAlgorithm) {
+		return nil, nil
+	}
+
+	keys, err := NewKeySet(cfg.SigningAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := keys.LoadSigningKeyFile(cfg.KeyID, cfg.PrivateKeyFile); err != nil {
+		return nil, err
 	}
+
+	previous, err := ParseKeyList(strings.Join(cfg.VerificationKeys, ","))
+	if err != nil {
+		return nil, err
+	}
+	for kid, path := range previous {
+		if err := keys.LoadVerificationKeyFile(kid, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
 }
 
-func jwtMiddleware(secretKey string) (*ginJwt.GinJWTMiddleware, error) {
-	return ginJwt.New(&ginJwt.GinJWTMiddleware{
-		Realm:      realm,
-		Key:        []byte(secretKey),
-		MaxRefresh: maxRefresh,
+func jwtMiddleware(cfg config.JWT, keys *KeySet) (*ginJwt.GinJWTMiddleware, error) {
+	mWare := &ginJwt.GinJWTMiddleware{
+		Realm:            realm,
+		SigningAlgorithm: cfg.SigningAlgorithm,
+		Key:              []byte(cfg.SigningSecret),
+		MaxRefresh:       cfg.RefreshTokenExpiry.Duration(),
 		PayloadFunc: func(data interface{}) ginJwt.MapClaims {
 			if v, ok := data.(*model.User); ok {
 				return ginJwt.MapClaims{
@@ -61,6 +126,28 @@ func jwtMiddleware(secretKey string) (*ginJwt.GinJWTMiddleware, error) {
 			return ginJwt.MapClaims{}
 		},
 		IdentityKey: identityKey,
-		Timeout:     accessTimeout,
-	})
+		Timeout:     cfg.AccessTokenExpiry.Duration(),
+	}
+
+	if keys != nil {
+		mWare.SigningAlgorithm = keys.Algorithm()
+		mWare.Key = nil
+		mWare.KeyFunc = keys.KeyFunc
+	}
+
+	return ginJwt.New(mWare)
+}
+
+// newEngine returns the engine of contexts that are not served over HTTP, falling back to the
+// request context so storage calls keep its deadline and values
+func newEngine() *gin.Engine {
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	return engine
+}
+
+// ForgetUser drops the cached copy of the user, so its next request sees a changed role, lock or
+// password at once
+func (m *Middleware) ForgetUser(userID int) {
+	m.cache.forget(strconv.Itoa(userID))
 }
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
+)
+
+const (
+	// RequestSessionIDInContext context key holding the session id of the request's access token
+	RequestSessionIDInContext = "request_session_id_in_context"
+	// sessionTouchInterval limits how often last-seen timestamps are written for a busy session
+	sessionTouchInterval = time.Minute
+)
+
+var (
+	// ErrSessionRevoked occurs when a token belongs to a session that was revoked or has expired
+	ErrSessionRevoked = errors.New("session has been revoked")
+)
+
+// checkSession verifies the session claim of a token belongs to the user and is still active,
+// recording that it was seen. Sessions are looked up within the tenant of the token.
+func (m *Middleware) checkSession(c context.Context, claims map[string]interface{}, userID int) (int, error) {
+	sessionID, err := claimInt(claims[claimsSessionID])
+	if err != nil {
+		// tokens without a session can not be revoked, so they are not accepted
+		return 0, ErrInvalidToken
+	}
+	tenantID, err := claimTenant(claims)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	session, err := m.sessionStorage.GetSessionByID(tenant.WithID(c, tenantID), sessionID)
+	if err != nil || session.UserID != userID {
+		return 0, ErrInvalidToken
+	}
+
+	now := m.jwt.TimeFunc()
+	if !session.Active(now) {
+		return 0, ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		go func(ctx context.Context) {
+			if err := m.sessionStorage.UpdateSessionLastSeen(ctx, sessionID, now); err != nil {
+				logging.Method(ctx, m.logger, "checkSession").Err(err).Msgf("checkSession: last seen update failed: %v", err)
+			}
+		}(tenant.WithID(logging.Detach(c), tenantID))
+	}
+
+	return sessionID, nil
+}
+
+// ContextWithSessionID returns a copy of ctx carrying the session id of the request
+func ContextWithSessionID(ctx context.Context, sessionID int) context.Context {
+	return context.WithValue(ctx, SessionContextKey, sessionID)
+}
+
+// SessionIDFromContext returns the session id stored by Authenticate, zero for API key requests
+func SessionIDFromContext(ctx context.Context) int {
+	sessionID, _ := ctx.Value(SessionContextKey).(int)
+	return sessionID
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	jwtGo "github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+)
+
+func Test_JwtAuthorization_RevokedSession(t *testing.T) {
+	m := newTestMiddleware(t)
+	user := &model.User{ID: 3, Kind: model.KindStaff}
+	m.cache.set("3", user)
+
+	tokens, err := m.GenerateTokens(newTestContext(), user)
+	require.NoError(t, err)
+
+	c := newTestContext()
+	c.Request.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	authorized, err := m.JwtAuthorization(c)
+	require.NoError(t, err)
+	require.Equal(t, user, authorized)
+	require.Equal(t, 1, c.GetInt(RequestSessionIDInContext))
+
+	require.NoError(t, m.sessionStorage.RevokeSessionByID(context.Background(), 1, time.Now()))
+	_, err = m.JwtAuthorization(c)
+	require.ErrorIs(t, err, ErrSessionRevoked)
+}
+
+func Test_AuthenticateHeader(t *testing.T) {
+	m := newTestMiddleware(t)
+	user := &model.User{ID: 3, Kind: model.KindStaff}
+	m.cache.set("3", user)
+
+	tokens, err := m.GenerateTokens(newTestContext(), user)
+	require.NoError(t, err)
+
+	ctx, err := m.AuthenticateHeader(context.Background(), http.Header{"Authorization": {"Bearer " + tokens.AccessToken}})
+	require.NoError(t, err)
+	authorized, err := UserFromContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, user, authorized)
+	require.Equal(t, 1, SessionIDFromContext(ctx))
+
+	// no credentials stays anonymous
+	ctx, err = m.AuthenticateHeader(context.Background(), http.Header{})
+	require.NoError(t, err)
+	_, err = UserFromContext(ctx)
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = m.AuthenticateHeader(context.Background(), http.Header{"Authorization": {"Bearer invalid"}})
+	require.Error(t, err)
+}
+
+func Test_LockedUser(t *testing.T) {
+	m := newTestMiddleware(t)
+	user := &model.User{ID: 3, Kind: model.KindStaff}
+	m.cache.set("3", user)
+	tokens, err := m.GenerateTokens(newTestContext(), user)
+	require.NoError(t, err)
+
+	// the user is locked once its sessions are revoked, cached copies must not let it back in
+	lockedAt := time.Now()
+	user.LockedAt = &lockedAt
+	_, err = m.evalKindForRelationship(context.Background(), user)
+	require.ErrorIs(t, err, ErrAccountSuspended)
+
+	_, err = m.sessionStorage.RevokeAllSessionsByUserID(context.Background(), 3, time.Now())
+	require.NoError(t, err)
+	_, err = m.AuthenticateHeader(context.Background(), http.Header{"Authorization": {"Bearer " + tokens.AccessToken}})
+	require.ErrorIs(t, err, ErrSessionRevoked)
+}
+
+func Test_JwtAuthorization_TokenType(t *testing.T) {
+	m := newTestMiddleware(t)
+	user := &model.User{ID: 3, Kind: model.KindStaff}
+	m.cache.set("3", user)
+
+	tokens, err := m.GenerateTokens(newTestContext(), user)
+	require.NoError(t, err)
+
+	c := newTestContext()
+	c.Request.Header.Set("Authorization", "Bearer "+tokens.RefreshToken)
+	_, err = m.JwtAuthorization(c)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// a token without a type is neither an access nor a refresh token
+	token, err := m.parseToken(tokens.AccessToken)
+	require.NoError(t, err)
+	delete(token.Claims.(jwtGo.MapClaims), claimsType)
+	untyped, err := m.signedString(token)
+	require.NoError(t, err)
+
+	c.Request.Header.Set("Authorization", "Bearer "+untyped)
+	_, err = m.JwtAuthorization(c)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = m.ValidateRefreshToken(zerolog.Nop(), newTestContext(), untyped)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
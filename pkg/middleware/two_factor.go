@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ginJwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	jwtGo "github.com/golang-jwt/jwt/v4"
+
+	graphModel "employee-management-system/graph/model"
+	"employee-management-system/model"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/pkg/totp"
+)
+
+const (
+	// challengeTimeout is how long a user has to supply the second factor after a password login
+	challengeTimeout     = time.Minute * 5
+	recoveryCodeMinInput = 10
+)
+
+var (
+	// ErrInvalidTwoFactorCode occurs when a TOTP or recovery code is wrong or was already used
+	ErrInvalidTwoFactorCode = errors.New("two-factor code is invalid")
+	// ErrTwoFactorAlreadyEnabled occurs when enrolling a user that already completed enrolment
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnrolled occurs when verifying a code for a user without a TOTP secret
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	// ErrTwoFactorRequired occurs when disabling two-factor authentication enforced for the user's role
+	ErrTwoFactorRequired = errors.New("two-factor authentication is required for this account")
+)
+
+// EnrollTwoFactor starts TOTP enrolment for the signed in user, or for the user of a challenge token
+// when their role enforces two-factor authentication and they have not enrolled yet. Enrolment is
+// completed by VerifyTwoFactor.
+func (m *Middleware) EnrollTwoFactor(c *gin.Context, challengeToken *string) (*graphModel.TwoFactorEnrollment, error) {
+	var (
+		userID int
+		ctx    context.Context
+	)
+	if challengeToken != nil {
+		id, tenantID, err := m.parseChallenge(*challengeToken)
+		if err != nil {
+			return nil, err
+		}
+		userID, ctx = id, tenant.WithID(c, tenantID)
+	} else {
+		current, err := UserFromContext(c.Request.Context())
+		if err != nil {
+			return nil, err
+		}
+		userID, ctx = current.ID, tenant.WithID(c, current.TenantID)
+	}
+
+	user, err := m.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, err := model.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, model.HashRecoveryCode(code))
+	}
+
+	if err := m.twoFactorStorage.StartEnrolment(ctx, user.ID, secret, hashes); err != nil {
+		return nil, err
+	}
+
+	account := fmt.Sprint(user.ID)
+	if user.UserName != nil {
+		account = *user.UserName
+	}
+
+	return &graphModel.TwoFactorEnrollment{
+		ProvisioningURI: totp.ProvisioningURI(m.config.TwoFactor.Issuer, account, secret),
+		Secret:          secret,
+		RecoveryCodes:   codes,
+	}, nil
+}
+
+// VerifyTwoFactor completes a login started by JwtAuthenticator. The first valid TOTP code after
+// enrolment also enables two-factor authentication; once enabled a recovery code is accepted instead.
+func (m *Middleware) VerifyTwoFactor(c *gin.Context, challengeToken, code string) (*graphModel.AuthResponse, error) {
+	userID, tenantID, err := m.parseChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	ctx := tenant.WithID(c, tenantID)
+
+	user, err := m.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPSecret == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	if err := m.checkSecondFactor(ctx, &user, code); err != nil {
+		logging.Method(c, m.logger, "VerifyTwoFactor").Warn().Msgf("VerifyTwoFactor: rejected code for user %d", user.ID)
+		return nil, err
+	}
+
+	if !user.TwoFactorEnabled() {
+		if err := m.twoFactorStorage.EnableTOTP(ctx, user.ID, m.jwt.TimeFunc()); err != nil {
+			return nil, err
+		}
+	}
+
+	authUser, err := m.evalKindForRelationship(c, &user)
+	if err != nil {
+		return nil, err
+	}
+	return m.issueTokens(c, authUser)
+}
+
+// DisableTwoFactor removes TOTP and recovery codes from the signed in user after checking a current code
+func (m *Middleware) DisableTwoFactor(c *gin.Context, code string) error {
+	current, err := UserFromContext(c.Request.Context())
+	if err != nil {
+		return err
+	}
+
+	ctx := tenant.WithID(c, current.TenantID)
+	user, err := m.userStorage.GetUserByID(ctx, current.ID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnrolled
+	}
+	if m.twoFactorRequired(&user) {
+		return ErrTwoFactorRequired
+	}
+	if err := m.checkSecondFactor(ctx, &user, code); err != nil {
+		return err
+	}
+
+	return m.twoFactorStorage.DisableTOTP(ctx, user.ID)
+}
+
+// checkSecondFactor accepts a TOTP code for a time step not used before or, once enrolment is
+// complete, an unused recovery code
+func (m *Middleware) checkSecondFactor(ctx context.Context, user *model.User, code string) error {
+	if step, ok := totp.Validate(*user.TOTPSecret, code, m.jwt.TimeFunc()); ok {
+		if err := m.twoFactorStorage.UseTOTPStep(ctx, user.ID, step); err != nil {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	if user.TwoFactorEnabled() && len(code) >= recoveryCodeMinInput {
+		if err := m.twoFactorStorage.UseRecoveryCode(ctx, user.ID, model.HashRecoveryCode(code), m.jwt.TimeFunc()); err == nil {
+			logging.Method(ctx, m.logger, "checkSecondFactor").Info().Msgf("VerifyTwoFactor: recovery code used by user %d", user.ID)
+			return nil
+		}
+	}
+
+	return ErrInvalidTwoFactorCode
+}
+
+// twoFactorChallenge answers a successful password login with a short-lived challenge token
+func (m *Middleware) twoFactorChallenge(user *model.User) (*graphModel.AuthResponse, error) {
+	token := jwtGo.New(jwtGo.GetSigningMethod(m.jwt.SigningAlgorithm))
+	claims := token.Claims.(jwtGo.MapClaims)
+	claims[claimsID] = user.ID
+	claims[claimsTenantID] = user.TenantID
+	claims[claimsExpiry] = m.jwt.TimeFunc().Add(challengeTimeout).Unix()
+	claims[claimsCreatedAt] = m.jwt.TimeFunc().Unix()
+	claims[claimsType] = tokenTypeChallenge
+
+	challenge, err := m.signedString(token)
+	if err != nil {
+		return nil, ginJwt.ErrFailedTokenCreation
+	}
+
+	return &graphModel.AuthResponse{
+		TwoFactorRequired:           true,
+		TwoFactorEnrollmentRequired: !user.TwoFactorEnabled(),
+		ChallengeToken:              &challenge,
+	}, nil
+}
+
+// parseChallenge returns the user and tenant ids of a valid, unexpired challenge token
+func (m *Middleware) parseChallenge(challengeToken string) (int, int, error) {
+	token, err := m.parseToken(challengeToken)
+	if err != nil || !token.Valid {
+		return 0, 0, ErrInvalidToken
+	}
+	claims, ok := token.Claims.(jwtGo.MapClaims)
+	if !ok || claims[claimsType] != tokenTypeChallenge {
+		return 0, 0, ErrInvalidToken
+	}
+	userID, err := claimInt(claims[claimsID])
+	if err != nil {
+		return 0, 0, err
+	}
+	tenantID, err := claimTenant(claims)
+	return userID, tenantID, err
+}
+
+// twoFactorRequired reports whether the user's role enforces two-factor authentication.
+// Service accounts authenticate with API keys and are never challenged.
+func (m *Middleware) twoFactorRequired(user *model.User) bool {
+	return !user.ServiceAccount && m.twoFactorKinds[user.Kind]
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+)
+
+func Test_TwoFactorChallenge(t *testing.T) {
+	m := newTestMiddleware(t)
+
+	resp, err := m.twoFactorChallenge(&model.User{ID: 12, TenantID: 3})
+	require.NoError(t, err)
+	require.True(t, resp.TwoFactorRequired)
+	require.True(t, resp.TwoFactorEnrollmentRequired)
+	require.Nil(t, resp.Token)
+
+	userID, tenantID, err := m.parseChallenge(*resp.ChallengeToken)
+	require.NoError(t, err)
+	require.Equal(t, 12, userID)
+	require.Equal(t, 3, tenantID)
+
+	// access tokens are not challenge tokens and the other way around
+	tokens, err := m.GenerateTokens(newTestContext(), &model.User{ID: 12})
+	require.NoError(t, err)
+	_, _, err = m.parseChallenge(tokens.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
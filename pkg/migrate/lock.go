@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"employee-management-system/pkg/config"
+)
+
+// lockPollInterval between attempts to take the PostgreSQL advisory lock
+const lockPollInterval = time.Millisecond * 500
+
+// lock takes an exclusive lock on a dedicated connection, waiting up to timeout for another holder to
+// release it. The lock goes with the connection, so a crashed process never leaves it behind.
+func (m *Migrator) lock(ctx context.Context, timeout time.Duration) (func(), error) {
+	switch m.driver {
+	case config.DatabaseDriverSQLServer:
+		return m.lockSQLServer(ctx, timeout)
+	case config.DatabaseDriverPostgres:
+		return m.lockPostgres(ctx, timeout)
+	default:
+		// SQLite serialises writers on the file itself
+		return func() {}, nil
+	}
+}
+
+func (m *Migrator) lockSQLServer(ctx context.Context, timeout time.Duration) (func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result int
+	err = conn.QueryRowContext(ctx, `DECLARE @result INT;
+EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2;
+SELECT @result;`, lockName, timeout.Milliseconds()).Scan(&result)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	// 0 granted, 1 granted after waiting, negative values are timeouts, deadlocks and errors
+	if result < 0 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w (sp_getapplock %d)", ErrLocked, result)
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", lockName)
+		_ = conn.Close()
+	}, nil
+}
+
+func (m *Migrator) lockPostgres(ctx context.Context, timeout time.Duration) (func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockName).Scan(&acquired); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("acquiring migration lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = conn.Close()
+			return nil, ErrLocked
+		}
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+		_ = conn.Close()
+	}, nil
+}
@@ -0,0 +1,242 @@
+// Package migrate applies the embedded goose migrations of package migrations and reports on them
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"employee-management-system/migrations"
+	"employee-management-system/pkg/config"
+)
+
+// lockName identifies the lock held while migrating
+const lockName = "employee-management-system:migrations"
+
+var (
+	// ErrLocked occurs when another process holds the migration lock for longer than the timeout
+	ErrLocked = errors.New("migrations are locked by another process")
+
+	// Drivers lists the configured database drivers that have migrations
+	Drivers = []string{config.DatabaseDriverSQLServer, config.DatabaseDriverPostgres, config.DatabaseDriverSQLite}
+
+	// gooseDialects maps configured drivers to goose dialects
+	gooseDialects = map[string]string{
+		config.DatabaseDriverSQLServer: "mssql",
+		config.DatabaseDriverPostgres:  "postgres",
+		config.DatabaseDriverSQLite:    "sqlite3",
+	}
+
+	// mu guards the goose dialect, goose keeps it and the base FS in package state
+	mu        sync.Mutex
+	setupOnce sync.Once
+)
+
+// Migrator applies the migrations of one driver to a database
+type Migrator struct {
+	db     *sql.DB
+	driver string
+}
+
+// New returns a Migrator for db, opened with the configured driver
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	if _, ok := gooseDialects[driver]; !ok {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+	return &Migrator{db: db, driver: driver}, nil
+}
+
+// use points goose at the migrations of m and returns the unlock function of the goose state
+func (m *Migrator) use() func() {
+	setupOnce.Do(func() { goose.SetBaseFS(migrations.FS) })
+	mu.Lock()
+	// the dialect is known valid, New checked it
+	_ = goose.SetDialect(gooseDialects[m.driver])
+	return mu.Unlock
+}
+
+// Up applies every pending migration while holding a database lock, so replicas starting together
+// migrate once and the others wait up to lockTimeout for them
+func (m *Migrator) Up(ctx context.Context, lockTimeout time.Duration) error {
+	unlock, err := m.lock(ctx, lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	defer m.use()()
+	return goose.UpContext(ctx, m.db, m.driver)
+}
+
+// Run executes a goose command, such as up, down-to or status
+func (m *Migrator) Run(ctx context.Context, command string, args ...string) error {
+	defer m.use()()
+	return goose.RunContext(ctx, command, m.db, m.driver, args...)
+}
+
+// Pending returns the migrations not applied to the database yet
+func (m *Migrator) Pending(ctx context.Context) (goose.Migrations, error) {
+	defer m.use()()
+	current, err := goose.GetDBVersionContext(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return collect(m.driver, current, goose.MaxVersion)
+}
+
+// DryRun writes the statements that migrating the database from its current version to target would
+// execute, without changing the database. Migrating down runs the Down sections, latest first.
+func (m *Migrator) DryRun(ctx context.Context, w io.Writer, target int64) error {
+	current, err := m.currentVersion(ctx)
+	if err != nil {
+		return err
+	}
+	selected, err := collect(m.driver, current, target)
+	if err != nil {
+		return err
+	}
+
+	down := target < current
+	if down {
+		sort.Sort(sort.Reverse(selected))
+	}
+	fmt.Fprintf(w, "-- at version %d, %d migrations to run\n", current, len(selected))
+	for _, migration := range selected {
+		script, err := parse(migration.Source)
+		if err != nil {
+			return err
+		}
+		statements, section := script.Up, "Up"
+		if down {
+			statements, section = script.Down, "Down"
+		}
+		fmt.Fprintf(w, "\n-- %s %s\n", migration.Source, section)
+		for _, statement := range statements {
+			fmt.Fprintln(w, statement)
+			// sqlcmd and SSMS need batches separated to run the output
+			if m.driver == config.DatabaseDriverSQLServer {
+				fmt.Fprintln(w, "GO")
+			}
+		}
+	}
+	return nil
+}
+
+// Target returns the version a goose command migrates the database to, for DryRun
+func (m *Migrator) Target(ctx context.Context, command string, version int64) (int64, error) {
+	current, err := m.currentVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch command {
+	case "up":
+		return goose.MaxVersion, nil
+	case "up-to", "down-to":
+		return version, nil
+	case "up-by-one":
+		next, err := collect(m.driver, current, goose.MaxVersion)
+		if err != nil || len(next) == 0 {
+			return current, err
+		}
+		return next[0].Version, nil
+	case "down":
+		previous, err := collect(m.driver, 0, current)
+		if err != nil || len(previous) < 2 {
+			return 0, err
+		}
+		return previous[len(previous)-2].Version, nil
+	default:
+		return 0, fmt.Errorf("%s has no dry run", command)
+	}
+}
+
+// currentVersion returns the version of the database without creating the goose version table,
+// zero when the database was never migrated
+func (m *Migrator) currentVersion(ctx context.Context) (int64, error) {
+	var query string
+	switch m.driver {
+	case config.DatabaseDriverSQLServer:
+		query = "SELECT CASE WHEN OBJECT_ID(@p1, 'U') IS NULL THEN 0 ELSE 1 END"
+	case config.DatabaseDriverPostgres:
+		query = "SELECT to_regclass($1) IS NOT NULL"
+	case config.DatabaseDriverSQLite:
+		query = "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = ?"
+	}
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, query, goose.TableName()).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	defer m.use()()
+	return goose.GetDBVersionContext(ctx, m.db)
+}
+
+// Validate parses the embedded migrations of every driver and checks each driver has the same
+// versions, reporting every problem at once
+func Validate() error {
+	var (
+		errs     []error
+		versions = map[string][]int64{}
+	)
+	for _, driver := range Drivers {
+		all, err := collect(driver, 0, goose.MaxVersion)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", driver, err))
+			continue
+		}
+		for _, migration := range all {
+			if _, err := parse(migration.Source); err != nil {
+				errs = append(errs, err)
+			}
+			versions[driver] = append(versions[driver], migration.Version)
+		}
+	}
+
+	reference := Drivers[0]
+	for _, driver := range Drivers[1:] {
+		if fmt.Sprint(versions[driver]) != fmt.Sprint(versions[reference]) {
+			errs = append(errs, fmt.Errorf("%s migrations %v differ from the %s ones %v", driver, versions[driver], reference, versions[reference]))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Files returns the names of the embedded migrations of a driver
+func Files(driver string) ([]string, error) {
+	return fs.Glob(migrations.FS, path.Join(driver, "*.sql"))
+}
+
+func collect(driver string, current, target int64) (goose.Migrations, error) {
+	setupOnce.Do(func() { goose.SetBaseFS(migrations.FS) })
+	selected, err := goose.CollectMigrations(driver, current, target)
+	if errors.Is(err, goose.ErrNoMigrationFiles) {
+		return nil, nil
+	}
+	return selected, err
+}
+
+func parse(source string) (Script, error) {
+	f, err := migrations.FS.Open(source)
+	if err != nil {
+		return Script{}, err
+	}
+	defer f.Close()
+
+	script, err := Parse(f)
+	if err != nil {
+		return Script{}, fmt.Errorf("%s: %w", source, err)
+	}
+	return script, nil
+}
@@ -0,0 +1,149 @@
+package migrate
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/database"
+)
+
+func Test_Parse(t *testing.T) {
+	script, err := Parse(strings.NewReader(`-- a header comment
+-- +goose Up
+CREATE TABLE a (id INT);
+-- between statements
+CREATE INDEX ix_a
+    ON a (id);
+
+-- +goose StatementBegin
+CREATE TRIGGER t ON a AFTER INSERT AS
+BEGIN
+    SELECT 1;
+END
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE a;
+`))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CREATE TABLE a (id INT);",
+		"CREATE INDEX ix_a\n    ON a (id);",
+		"CREATE TRIGGER t ON a AFTER INSERT AS\nBEGIN\n    SELECT 1;\nEND",
+	}, script.Up)
+	require.Equal(t, []string{"DROP TABLE a;"}, script.Down)
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		sql string
+		err string
+	}{
+		"missing up":            {sql: "-- +goose Down\nDROP TABLE a;\n", err: "unexpected -- +goose Down"},
+		"missing down":          {sql: "-- +goose Up\nCREATE TABLE a (id INT);\n", err: "missing -- +goose Down"},
+		"empty up":              {sql: "-- +goose Up\n-- +goose Down\nDROP TABLE a;\n", err: "no statement"},
+		"statement before up":   {sql: "CREATE TABLE a (id INT);\n-- +goose Up\n", err: "line 1: statement before"},
+		"unterminated":          {sql: "-- +goose Up\nCREATE TABLE a (id INT)\n-- +goose Down\nDROP TABLE a;\n", err: "line 3: statement before -- +goose Down"},
+		"unterminated at end":   {sql: "-- +goose Up\nCREATE TABLE a (id INT);\n-- +goose Down\nDROP TABLE a\n", err: "last statement"},
+		"block never ended":     {sql: "-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n", err: "never ended"},
+		"end without begin":     {sql: "-- +goose Up\n-- +goose StatementEnd\n", err: "without"},
+		"bare goose annotation": {sql: "-- +goose\n", err: "missing -- +goose Up"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(test.sql))
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+// every embedded migration must parse, so a broken file fails CI rather than a deployment
+func Test_Validate(t *testing.T) {
+	require.NoError(t, Validate())
+
+	for _, driver := range Drivers {
+		files, err := Files(driver)
+		require.NoError(t, err)
+		require.NotEmpty(t, files, driver)
+	}
+}
+
+// models lists every model stored in its own table
+var models = []interface{}{
+	&model.User{}, &model.Department{}, &model.Employee{}, &model.APIKey{}, &model.RecoveryCode{}, &model.Session{},
+	&model.LeaveRecord{}, &model.Tenant{}, &model.AuditEntry{}, &model.Document{},
+}
+
+// Test_SQLite applies every migration up and down, checking the tables match the gorm models in between
+func Test_SQLite(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Database{
+		Driver: config.DatabaseDriverSQLite,
+		Name:   "file:" + filepath.Join(t.TempDir(), "migrate.db") + "?_foreign_keys=on",
+	}
+	db, err := database.Open(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := New(db, cfg.Driver)
+	require.NoError(t, err)
+	gormDB, err := gorm.Open(sqlite.Dialector{Conn: db}, &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, migrator.Up(ctx, time.Second))
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	requireSchema(t, gormDB)
+
+	// the models can be stored as they are
+	userName := "migrate"
+	user := model.User{UserName: &userName, Password: "hash", Kind: model.KindStaff}
+	require.NoError(t, gormDB.Create(&user).Error)
+	department := model.Department{DepartmentName: "Engineering"}
+	require.NoError(t, gormDB.Create(&department).Error)
+	require.NoError(t, gormDB.Create(&model.Employee{UserID: user.ID, FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", DepartmentID: department.ID}).Error)
+	require.NoError(t, gormDB.Create(&model.Employee{FirstName: "No", LastName: "User", Email: "no@example.com"}).Error)
+	require.NoError(t, gormDB.Exec("DELETE FROM employees; DELETE FROM departments; DELETE FROM users").Error)
+
+	require.NoError(t, migrator.Run(ctx, "reset"))
+	for _, m := range models {
+		require.False(t, gormDB.Migrator().HasTable(m), "%T", m)
+	}
+
+	// down left nothing behind that stops migrating up again
+	require.NoError(t, migrator.Up(ctx, time.Second))
+	requireSchema(t, gormDB)
+}
+
+// requireSchema checks every model has a table with exactly the columns of its fields
+func requireSchema(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(m))
+
+		var want []string
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				want = append(want, field.DBName)
+			}
+		}
+		columns, err := db.Migrator().ColumnTypes(m)
+		require.NoError(t, err, stmt.Schema.Table)
+		var got []string
+		for _, column := range columns {
+			got = append(got, column.Name())
+		}
+		require.ElementsMatch(t, want, got, stmt.Schema.Table)
+	}
+}
@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	annotationUp             = "-- +goose Up"
+	annotationDown           = "-- +goose Down"
+	annotationStatementBegin = "-- +goose StatementBegin"
+	annotationStatementEnd   = "-- +goose StatementEnd"
+	annotationPrefix         = "-- +goose"
+)
+
+// Script is a parsed SQL migration
+type Script struct {
+	Up   []string
+	Down []string
+}
+
+// Parse splits a goose SQL migration into the statements of its Up and Down sections, the way goose
+// runs them: a StatementBegin/StatementEnd block is one statement, elsewhere statements end at a
+// line ending with a semicolon
+func Parse(r io.Reader) (Script, error) {
+	var (
+		script    Script
+		section   *[]string
+		statement strings.Builder
+		inBlock   bool
+		seenUp    bool
+		seenDown  bool
+	)
+	flush := func() {
+		if s := strings.TrimSpace(statement.String()); s != "" {
+			*section = append(*section, s)
+		}
+		statement.Reset()
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, annotationPrefix):
+			fields := append(strings.Fields(trimmed), "")
+			// other annotations, such as NO TRANSACTION, do not change how statements are split
+			switch fields[2] {
+			case "Up":
+				if seenUp || seenDown || inBlock {
+					return Script{}, fmt.Errorf("line %d: unexpected %s", number, annotationUp)
+				}
+				seenUp, section = true, &script.Up
+			case "Down":
+				if !seenUp || seenDown || inBlock {
+					return Script{}, fmt.Errorf("line %d: unexpected %s", number, annotationDown)
+				}
+				if strings.TrimSpace(statement.String()) != "" {
+					return Script{}, fmt.Errorf("line %d: statement before %s is not terminated by a semicolon", number, annotationDown)
+				}
+				seenDown, section = true, &script.Down
+			case "StatementBegin":
+				if section == nil || inBlock {
+					return Script{}, fmt.Errorf("line %d: unexpected %s", number, annotationStatementBegin)
+				}
+				if strings.TrimSpace(statement.String()) != "" {
+					return Script{}, fmt.Errorf("line %d: statement before %s is not terminated by a semicolon", number, annotationStatementBegin)
+				}
+				inBlock = true
+			case "StatementEnd":
+				if !inBlock {
+					return Script{}, fmt.Errorf("line %d: %s without %s", number, annotationStatementEnd, annotationStatementBegin)
+				}
+				inBlock = false
+				flush()
+			}
+		case section == nil:
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return Script{}, fmt.Errorf("line %d: statement before %s", number, annotationUp)
+			}
+		case !inBlock && strings.HasPrefix(trimmed, "--") && statement.Len() == 0:
+			// comments between statements are not sent to the database
+		default:
+			statement.WriteString(line)
+			statement.WriteString("\n")
+			if !inBlock && strings.HasSuffix(trimmed, ";") {
+				flush()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Script{}, err
+	}
+
+	switch {
+	case !seenUp:
+		return Script{}, errors.New("missing " + annotationUp)
+	case inBlock:
+		return Script{}, errors.New(annotationStatementBegin + " is never ended")
+	case strings.TrimSpace(statement.String()) != "":
+		return Script{}, errors.New("last statement is not terminated by a semicolon")
+	case len(script.Up) == 0:
+		return Script{}, errors.New("the Up section has no statement")
+	case !seenDown:
+		return Script{}, errors.New("missing " + annotationDown + ", every migration must be reversible")
+	}
+	return script, nil
+}
@@ -0,0 +1,225 @@
+// Package pii encrypts personal data with AES-256-GCM under the keys of a keyring file and computes
+// blind indexes, so encrypted values can still be looked up by equality
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// prefix starts every encrypted value, followed by the key id, a colon and the base64 encoded
+	// nonce and ciphertext
+	prefix  = "pii:v1:"
+	keySize = 32
+)
+
+var (
+	// ErrUnknownKey the value is encrypted under a key missing from the keyring
+	ErrUnknownKey = errors.New("pii: unknown key")
+	// ErrMalformed the value is not a valid encrypted value or keyring
+	ErrMalformed = errors.New("pii: malformed value")
+)
+
+type (
+	// Keyring holds the data keys by id, new values are encrypted under the primary one and values
+	// encrypted under any of them are decrypted
+	Keyring struct {
+		primary  string
+		aeads    map[string]cipher.AEAD
+		indexKey []byte
+	}
+
+	// file is the JSON layout of a keyring file, keys are base64 encoded 32 byte keys
+	file struct {
+		Primary  string            `json:"primary"`
+		IndexKey string            `json:"index_key"`
+		Keys     map[string]string `json:"keys"`
+	}
+)
+
+// LoadKeyring reads the keyring file at path
+func LoadKeyring(path string) (*Keyring, error) {
+	f, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.keyring()
+}
+
+// Primary returns the id of the key new values are encrypted under
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext under the primary key. additionalData, such as the column name, must be
+// given again to decrypt, so a value copied to another column does not decrypt.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) (string, error) {
+	aead := k.aeads[k.primary]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, additionalData)
+	return prefix + k.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value returned by Encrypt under any key of the keyring
+func (k *Keyring) Decrypt(value string, additionalData []byte) ([]byte, error) {
+	keyID, ok := KeyID(value)
+	if !ok {
+		return nil, ErrMalformed
+	}
+	aead, ok := k.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(value[len(prefix)+len(keyID)+1:])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plaintext, nil
+}
+
+// BlindIndex returns a keyed hash of value, case and surrounding space ignored, that equal values
+// share. A nil keyring hashes without a key, as values are stored unencrypted then anyway.
+func (k *Keyring) BlindIndex(value string) string {
+	var key []byte
+	if k != nil {
+		key = k.indexKey
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value was returned by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID returns the id of the key value is encrypted under
+func KeyID(value string) (string, bool) {
+	if !IsEncrypted(value) {
+		return "", false
+	}
+	keyID, _, ok := strings.Cut(value[len(prefix):], ":")
+	return keyID, ok && keyID != ""
+}
+
+// Rotate adds a new key to the keyring file at path and makes it the primary one, creating the file
+// with a blind index key when it does not exist. Values encrypted under the previous keys still
+// decrypt until they are encrypted again. It returns the id of the new key.
+func Rotate(path string, now time.Time) (string, error) {
+	f, err := readFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		indexKey, err := newKey()
+		if err != nil {
+			return "", err
+		}
+		f = file{IndexKey: indexKey, Keys: map[string]string{}}
+	case err != nil:
+		return "", err
+	}
+
+	keyID := now.UTC().Format("20060102T150405Z")
+	if _, ok := f.Keys[keyID]; ok {
+		return "", fmt.Errorf("pii: key %s already exists", keyID)
+	}
+	key, err := newKey()
+	if err != nil {
+		return "", err
+	}
+	f.Keys[keyID] = key
+	f.Primary = keyID
+	if _, err := f.keyring(); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	// written aside and renamed so a failure never leaves a truncated keyring behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return "", err
+	}
+	return keyID, os.Rename(tmp, path)
+}
+
+func readFile(path string) (file, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file{}, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return file{}, fmt.Errorf("%w: keyring %s: %v", ErrMalformed, path, err)
+	}
+	return f, nil
+}
+
+func (f file) keyring() (*Keyring, error) {
+	indexKey, err := decodeKey(f.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: index key: %v", ErrMalformed, err)
+	}
+	k := &Keyring{primary: f.Primary, aeads: make(map[string]cipher.AEAD, len(f.Keys)), indexKey: indexKey}
+	for id, encoded := range f.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: invalid key id %q", ErrMalformed, id)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s: %v", ErrMalformed, id, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if k.aeads[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := k.aeads[f.Primary]; !ok {
+		return nil, fmt.Errorf("%w: primary key %q is not in the keyring", ErrMalformed, f.Primary)
+	}
+	return k, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key is %d bytes, expected %d", len(key), keySize)
+	}
+	return key, nil
+}
+
+func newKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
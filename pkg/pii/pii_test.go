@@ -0,0 +1,78 @@
+package pii
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncryptRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	now := time.Date(2023, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	first, err := Rotate(path, now)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	k, err := LoadKeyring(path)
+	require.NoError(t, err)
+	require.Equal(t, first, k.Primary())
+	old, err := k.Encrypt([]byte("jane@company.com"), []byte("employees.email"))
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(old))
+	require.NotContains(t, old, "jane")
+	again, err := k.Encrypt([]byte("jane@company.com"), []byte("employees.email"))
+	require.NoError(t, err)
+	require.NotEqual(t, old, again, "every value gets its own nonce")
+
+	// the value only opens with the same additional data
+	_, err = k.Decrypt(old, []byte("employees.dob"))
+	require.ErrorIs(t, err, ErrMalformed)
+
+	// a rotated keyring encrypts under the new key and still opens the old values
+	second, err := Rotate(path, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+	rotated, err := LoadKeyring(path)
+	require.NoError(t, err)
+	plaintext, err := rotated.Decrypt(old, []byte("employees.email"))
+	require.NoError(t, err)
+	require.Equal(t, "jane@company.com", string(plaintext))
+	current, err := rotated.Encrypt(plaintext, nil)
+	require.NoError(t, err)
+	keyID, ok := KeyID(current)
+	require.True(t, ok)
+	require.Equal(t, second, keyID)
+
+	// the blind index survives rotation and ignores case
+	require.Equal(t, k.BlindIndex("jane@company.com"), rotated.BlindIndex(" Jane@Company.com"))
+	require.NotEqual(t, k.BlindIndex("jane@company.com"), k.BlindIndex("john@company.com"))
+	var none *Keyring
+	require.NotEqual(t, none.BlindIndex("jane@company.com"), k.BlindIndex("jane@company.com"))
+
+	_, err = k.Decrypt(current, nil)
+	require.ErrorIs(t, err, ErrUnknownKey)
+	_, err = k.Decrypt("jane@company.com", nil)
+	require.ErrorIs(t, err, ErrMalformed)
+}
+
+func Test_LoadKeyring_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"json":    "{",
+		"primary": `{"primary":"k2","index_key":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=","keys":{"k1":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}}`,
+		"short":   `{"primary":"k1","index_key":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=","keys":{"k1":"AAAA"}}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := LoadKeyring(path)
+		require.ErrorIs(t, err, ErrMalformed, name)
+	}
+	_, err := LoadKeyring(filepath.Join(dir, "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,134 @@
+package pii
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the gorm serializer of the model fields encrypted at rest, string and
+// time.Time fields tagged `gorm:"serializer:pii"`
+const SerializerName = "pii"
+
+// timeLayouts are tried in turn to read back a time, the last ones are how the databases render
+// dates stored before encryption
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02"}
+
+// ErrNoKeyring a value is encrypted but no keyring is in use
+var ErrNoKeyring = errors.New("pii: value is encrypted but no keyring is configured (PII_KEYRING_FILE)")
+
+var current atomic.Pointer[Keyring]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Use makes k the keyring of the serializer, nil stores new values unencrypted
+func Use(k *Keyring) {
+	current.Store(k)
+}
+
+// Current returns the keyring of the serializer, nil when values are stored unencrypted
+func Current() *Keyring {
+	return current.Load()
+}
+
+// Serializer encrypts fields under the primary key of the current keyring, bound to their table and
+// column. Values stored unencrypted, before a keyring was used, are read as they are until
+// encrypted again.
+type Serializer struct{}
+
+// Value returns the encrypted field value, a zero time is stored as NULL
+func (Serializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var plaintext string
+	switch v := fieldValue.(type) {
+	case string:
+		plaintext = v
+	case time.Time:
+		if v.IsZero() {
+			return nil, nil
+		}
+		plaintext = v.Format(time.RFC3339Nano)
+	default:
+		return nil, fmt.Errorf("pii: unsupported type %T of %s", fieldValue, field.Name)
+	}
+
+	k := Current()
+	if k == nil {
+		return plaintext, nil
+	}
+	return k.Encrypt([]byte(plaintext), additionalData(field))
+}
+
+// Scan decrypts dbValue into the field of dst
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value := reflect.New(field.FieldType).Elem()
+	plaintext, err := decrypt(field, dbValue)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field.Name, err)
+	}
+
+	switch target := value.Addr().Interface().(type) {
+	case *string:
+		*target = plaintext
+	case *time.Time:
+		if t, ok := dbValue.(time.Time); ok {
+			*target = t
+			break
+		}
+		if plaintext != "" {
+			if *target, err = parseTime(plaintext); err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("pii: unsupported type %s of %s", field.FieldType, field.Name)
+	}
+	field.ReflectValueOf(ctx, dst).Set(value)
+	return nil
+}
+
+func decrypt(field *schema.Field, dbValue interface{}) (string, error) {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil, time.Time:
+		return "", nil
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return "", fmt.Errorf("pii: unsupported database value %T", dbValue)
+	}
+	if !IsEncrypted(stored) {
+		return stored, nil
+	}
+
+	k := Current()
+	if k == nil {
+		return "", ErrNoKeyring
+	}
+	plaintext, err := k.Decrypt(stored, additionalData(field))
+	return string(plaintext), err
+}
+
+func parseTime(value string) (time.Time, error) {
+	var err error
+	for _, layout := range timeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// additionalData binds a value to its column
+func additionalData(field *schema.Field) []byte {
+	return []byte(field.Schema.Table + "." + field.DBName)
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/middleware"
+)
+
+const (
+	packageName = "ratelimit"
+	// errorCode is the API error code of a rejected request
+	errorCode = apierror.CodeRateLimited
+)
+
+var (
+	// ErrRateLimited occurs when the caller has used up its bucket
+	ErrRateLimited = errors.New("rate limit exceeded")
+)
+
+// Limiter applies the general bucket to every request and the stricter authentication bucket to
+// the authentication mutations
+type Limiter struct {
+	logger         zerolog.Logger
+	store          Store
+	limit          Limit
+	authLimit      Limit
+	authOperations map[string]bool
+	now            func() time.Time
+}
+
+// New Limiter taking tokens from store
+func New(z zerolog.Logger, cfg config.RateLimit, store Store) *Limiter {
+	operations := make(map[string]bool, len(cfg.AuthOperations))
+	for _, name := range cfg.AuthOperations {
+		operations[name] = true
+	}
+	return &Limiter{
+		logger:         z.With().Str(helper.LogStrKeyModule, packageName).Logger(),
+		store:          store,
+		limit:          Limit{Rate: float64(cfg.RequestsPerMinute) / 60, Burst: cfg.Burst},
+		authLimit:      Limit{Rate: float64(cfg.AuthRequestsPerMinute) / 60, Burst: cfg.AuthBurst},
+		authOperations: operations,
+		now:            time.Now,
+	}
+}
+
+// HTTP is a gin middleware answering 429 once the caller's bucket is empty. It must run after
+// Authenticate so authenticated callers are limited by identity rather than address.
+func (l *Limiter) HTTP() gin.HandlerFunc {
+	return l.limitBy(l.callerKey)
+}
+
+// IP is a gin middleware answering 429 once the client IP's bucket is empty. It runs before
+// Authenticate, so requests with bad credentials, which Authenticate rejects, are limited and
+// guessing keys or tokens costs a token per attempt.
+func (l *Limiter) IP() gin.HandlerFunc {
+	return l.limitBy(func(c *gin.Context) string {
+		return "addr:" + c.ClientIP()
+	})
+}
+
+func (l *Limiter) limitBy(key func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, ok := l.take(c, key(c), l.limit)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(l.limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		if ok {
+			c.Next()
+			return
+		}
+
+		retryAfter := setRetryAfter(c, result.RetryAfter)
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"errors": gqlerror.List{rateLimitError(retryAfter)},
+		})
+	}
+}
+
+// callerKey identifies the caller by API key, then authenticated user, then client IP
+func (l *Limiter) callerKey(c *gin.Context) string {
+	if secret := model.APIKeySecret(c.GetHeader(middleware.APIKeyHeader)); secret.Prefix() != "" {
+		return "key:" + secret.Prefix()
+	}
+	if user, err := middleware.UserFromContext(c.Request.Context()); err == nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// take reports whether the request may proceed. A failing store lets requests through, an outage of
+// a shared backend must not take the API down with it.
+func (l *Limiter) take(ctx context.Context, key string, limit Limit) (Result, bool) {
+	result, err := l.store.Take(ctx, key, limit, l.now())
+	if err != nil {
+		logging.Method(ctx, l.logger, "take").Err(err).Msgf("Limiter::take error: %v", err)
+		return Result{Allowed: true}, true
+	}
+	if !result.Allowed {
+		logging.Method(ctx, l.logger, "take").Warn().Msgf("Limiter::take %s limited for %s", key, result.RetryAfter)
+	}
+	return result, result.Allowed
+}
+
+type graphqlExtension struct {
+	l *Limiter
+}
+
+var (
+	_ graphql.HandlerExtension = graphqlExtension{}
+	_ graphql.FieldInterceptor = graphqlExtension{}
+)
+
+// GraphQL returns a gqlgen extension applying the authentication bucket, keyed by client IP, to the
+// configured mutations
+func (l *Limiter) GraphQL() graphql.HandlerExtension {
+	return graphqlExtension{l: l}
+}
+
+// ExtensionName of the gqlgen extension
+func (graphqlExtension) ExtensionName() string {
+	return "RateLimit"
+}
+
+// Validate the extension against the schema, there is nothing to check
+func (graphqlExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField rejects an authentication mutation once the caller's bucket is empty
+func (e graphqlExtension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" || !e.l.authOperations[fc.Field.Name] {
+		return next(ctx)
+	}
+	gc, err := middleware.GinContextFromContext(ctx)
+	if err != nil {
+		return next(ctx)
+	}
+
+	result, ok := e.l.take(ctx, "auth:"+gc.ClientIP(), e.l.authLimit)
+	if ok {
+		return next(ctx)
+	}
+	return nil, rateLimitError(setRetryAfter(gc, result.RetryAfter))
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounded up, and returns the seconds
+func setRetryAfter(c *gin.Context, wait time.Duration) int {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	return seconds
+}
+
+func rateLimitError(retryAfter int) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: ErrRateLimited.Error(),
+		Extensions: map[string]interface{}{
+			"code":       errorCode,
+			"retryAfter": retryAfter,
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/middleware"
+)
+
+func Test_MemoryStore_Take(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take(ctx, "a", limit, now)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err := store.Take(ctx, "a", limit, now)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+
+	// other keys have their own bucket
+	result, _ = store.Take(ctx, "b", limit, now)
+	require.True(t, result.Allowed)
+
+	result, _ = store.Take(ctx, "a", limit, now.Add(time.Second))
+	require.True(t, result.Allowed)
+}
+
+func Test_MemoryStore_Sweep(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 1}
+	now := time.Now()
+
+	_, _ = store.Take(context.Background(), "idle", limit, now)
+	_, _ = store.Take(context.Background(), "busy", limit, now.Add(sweepInterval*2))
+	require.NotContains(t, store.buckets, "idle")
+	require.Contains(t, store.buckets, "busy")
+}
+
+func Test_Limiter_HTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default().RateLimit
+	cfg.Burst = 1
+
+	limiter := New(zerolog.Nop(), cfg, NewMemoryStore())
+	r := gin.New()
+	r.POST("/query", limiter.HTTP(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, send("10.0.0.1").Code)
+
+	w := send("10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "1", w.Header().Get("Retry-After"))
+	require.Contains(t, w.Body.String(), `"code":"RATE_LIMITED"`)
+
+	require.Equal(t, http.StatusOK, send("10.0.0.2").Code)
+}
+
+func Test_Limiter_IP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default().RateLimit
+	cfg.Burst = 2
+
+	limiter := New(zerolog.Nop(), cfg, NewMemoryStore())
+	r := gin.New()
+	// stands in for Authenticate rejecting every key
+	reject := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	r.POST("/query", limiter.IP(), reject, limiter.HTTP())
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(middleware.APIKeyHeader, "ems_0123abcd_guess")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, send())
+	require.Equal(t, http.StatusUnauthorized, send())
+	require.Equal(t, http.StatusTooManyRequests, send())
+}
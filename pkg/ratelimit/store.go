@@ -0,0 +1,104 @@
+// Package ratelimit limits requests with token buckets keyed by user, API key or client IP
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// Limit of a bucket: it refills at Rate tokens per second and holds at most Burst tokens
+	Limit struct {
+		Rate  float64
+		Burst int
+	}
+
+	// Result of taking a token
+	Result struct {
+		Allowed   bool
+		Remaining int
+		// RetryAfter is how long until a token is available again when not Allowed
+		RetryAfter time.Duration
+	}
+
+	// Store keeps the buckets. MemoryStore suits a single instance, replicas behind a load balancer
+	// need a shared implementation, e.g. on Redis, so a client cannot multiply its limit.
+	Store interface {
+		Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	}
+
+	bucket struct {
+		limit   Limit
+		tokens  float64
+		updated time.Time
+	}
+
+	// MemoryStore is an in-process Store
+	MemoryStore struct {
+		mu        sync.Mutex
+		buckets   map[string]*bucket
+		lastSweep time.Time
+	}
+)
+
+// sweepInterval is how often buckets that have refilled completely are dropped
+const sweepInterval = time.Minute
+
+// NewMemoryStore creates an empty in-process Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Take removes a token from the bucket of key, refilling it for the time elapsed since the last call
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return Result{RetryAfter: wait}, nil
+	}
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.updated = now
+	}
+}
+
+// full reports whether the bucket has refilled completely by now
+func (b *bucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate >= float64(b.limit.Burst)
+}
+
+// sweep drops buckets idle long enough to be full again, they are recreated full on demand
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if b.full(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package server runs the HTTP server and shuts it down gracefully on SIGINT/SIGTERM
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/helper"
+)
+
+const packageName = "server"
+
+// Server wraps an http.Server so long-lived connections, such as GraphQL websocket subscriptions,
+// are drained alongside ordinary requests on shutdown
+type Server struct {
+	logger          zerolog.Logger
+	http            *http.Server
+	shutdownTimeout time.Duration
+
+	// streams counts requests the http.Server stops tracking once the connection is hijacked
+	streams sync.WaitGroup
+	// closing is cancelled when shutdown starts so streams can finish their work and return
+	closing context.Context
+	close   context.CancelFunc
+}
+
+// New Server serving handler with the timeouts from cfg
+func New(z zerolog.Logger, cfg config.Server, handler http.Handler) *Server {
+	closing, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		logger: z.With().Str(helper.LogStrKeyModule, packageName).Logger(),
+		http: &http.Server{
+			Addr:              cfg.Address(),
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout.Duration(),
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout.Duration(),
+			WriteTimeout:      cfg.WriteTimeout.Duration(),
+			IdleTimeout:       cfg.IdleTimeout.Duration(),
+		},
+		shutdownTimeout: cfg.ShutdownTimeout.Duration(),
+		closing:         closing,
+		close:           cancel,
+	}
+	s.http.RegisterOnShutdown(cancel)
+	return s
+}
+
+// Stream is a gin middleware for handlers that may hijack the connection. The request context is
+// cancelled when shutdown starts and shutdown waits for the handler to return.
+func (s *Server) Stream() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.streams.Add(1)
+		defer s.streams.Done()
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-s.closing.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Run serves until ctx is done or SIGINT/SIGTERM is received, then stops accepting connections and
+// waits up to the shutdown timeout for in-flight requests and streams to finish
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		s.logger.Info().Msgf("Run: listening on %s", s.http.Addr)
+		serveErr <- s.http.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.close()
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info().Msgf("Run: shutting down, draining for up to %s", s.shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := s.http.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		s.logger.Warn().Msg("Run: in-flight requests did not finish in time")
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.streams.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+		s.logger.Warn().Msg("Run: streams did not finish in time")
+		err = errors.Join(err, s.http.Close())
+	}
+
+	if serveErr := <-serveErr; !errors.Is(serveErr, http.ErrServerClosed) {
+		err = errors.Join(err, serveErr)
+	}
+	return err
+}
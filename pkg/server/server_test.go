@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/pkg/config"
+)
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func Test_Run_DrainsRequestsAndStreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default().Server
+	cfg.Port = freePort(t)
+	cfg.ShutdownTimeout = config.Duration(time.Second * 5)
+
+	r := gin.New()
+	s := New(zerolog.Nop(), cfg, r)
+
+	started := make(chan struct{}, 2)
+	streamClosed := make(chan struct{})
+	r.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		time.Sleep(time.Millisecond * 200)
+		c.String(http.StatusOK, "done")
+	})
+	r.GET("/stream", s.Stream(), func(c *gin.Context) {
+		started <- struct{}{}
+		<-c.Request.Context().Done()
+		close(streamClosed)
+		c.Status(http.StatusNoContent)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- s.Run(ctx) }()
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(cfg.Port))
+	base := "http://" + addr
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, time.Millisecond*10)
+
+	slow := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			slow <- 0
+			return
+		}
+		resp.Body.Close()
+		slow <- resp.StatusCode
+	}()
+	go func() {
+		if resp, err := http.Get(base + "/stream"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	<-started
+
+	cancel()
+	require.NoError(t, <-runErr)
+	require.Equal(t, http.StatusOK, <-slow)
+
+	select {
+	case <-streamClosed:
+	default:
+		t.Fatal("stream was not cancelled on shutdown")
+	}
+}
@@ -0,0 +1,40 @@
+// Package tenant carries the company a call acts for, so storage can scope every query to it
+package tenant
+
+import (
+	"context"
+
+	"employee-management-system/pkg/helper"
+)
+
+const (
+	// DefaultID is the company created by the migrations, it owns every row stored before tenants
+	// existed and the tokens issued without a tenant claim
+	DefaultID = 1
+
+	idKey       = helper.Key("TenantIDKey")
+	unscopedKey = helper.Key("TenantUnscopedKey")
+)
+
+// WithID returns a copy of ctx acting for the tenant id
+func WithID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, idKey, id)
+}
+
+// FromContext returns the tenant ctx acts for, if any
+func FromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(idKey).(int)
+	return id, ok && id > 0
+}
+
+// Unscoped returns a copy of ctx reading across tenants. Only authentication uses it, to find the
+// caller and so its tenant from globally unique credentials.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey, true)
+}
+
+// IsUnscoped reports whether ctx reads across tenants
+func IsUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey).(bool)
+	return unscoped
+}
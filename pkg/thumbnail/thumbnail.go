@@ -0,0 +1,202 @@
+// Package thumbnail turns uploaded JPEG and PNG pictures into square JPEG thumbnails. Only the pixels
+// are encoded again, so EXIF and any other metadata of the upload is left behind; the EXIF
+// orientation is applied first so pictures taken on their side come out upright.
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	// MaxPixels bounds the pictures decoded, a small file may still declare a huge picture. It is
+	// about a camera photo, every pixel is read once to scale it down.
+	MaxPixels = 16_000_000
+	// quality of the JPEG thumbnails
+	quality = 85
+)
+
+var (
+	// ErrUnsupported the picture is not a JPEG or PNG
+	ErrUnsupported = errors.New("picture is not a JPEG or PNG")
+	// ErrTooLarge the picture has more than MaxPixels pixels
+	ErrTooLarge = errors.New("picture has too many pixels")
+)
+
+// Decode decodes the JPEG or PNG picture in data, turned upright according to its EXIF orientation
+func Decode(data []byte) (image.Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, ErrUnsupported
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	var img image.Image
+	if format == "jpeg" {
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		img, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if format == "jpeg" {
+		if o := orientation(data); o > 1 && o <= 8 {
+			img = &oriented{Image: img, orientation: o}
+		}
+	}
+	return img, nil
+}
+
+// Square returns the centered square of img scaled to size by size pixels. Transparent pixels are
+// put on white, JPEG has no transparency.
+func Square(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	left := bounds.Min.X + (bounds.Dx()-side)/2
+	top := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	thumb := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := span(top, side, size, y)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(left, side, size, x)
+			// averages the source pixels the thumbnail pixel covers
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					white := uint64(0xffff - ca)
+					r, g, b, n = r+uint64(cr)+white, g+uint64(cg)+white, b+uint64(cb)+white, n+1
+				}
+			}
+			thumb.SetRGBA(x, y, color.RGBA{R: uint8((r / n) >> 8), G: uint8((g / n) >> 8), B: uint8((b / n) >> 8), A: 0xff})
+		}
+	}
+	return thumb
+}
+
+// Encode writes img to w as a JPEG
+func Encode(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// span returns the source pixels [from, to) thumbnail pixel i of size covers out of side pixels
+// starting at start, at least one so pictures smaller than the thumbnail are scaled up
+func span(start, side, size, i int) (int, int) {
+	from := start + i*side/size
+	to := start + (i+1)*side/size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// oriented is an image turned upright according to an EXIF orientation from 2 to 8
+type oriented struct {
+	image.Image
+	orientation int
+}
+
+// transposed reports whether the orientation swaps width and height
+func (o *oriented) transposed() bool {
+	return o.orientation >= 5
+}
+
+func (o *oriented) Bounds() image.Rectangle {
+	bounds := o.Image.Bounds()
+	if o.transposed() {
+		return image.Rect(0, 0, bounds.Dy(), bounds.Dx())
+	}
+	return image.Rect(0, 0, bounds.Dx(), bounds.Dy())
+}
+
+func (o *oriented) At(x, y int) color.Color {
+	bounds := o.Image.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	var sx, sy int
+	switch o.orientation {
+	case 2: // mirrored
+		sx, sy = w-1-x, y
+	case 3: // upside down
+		sx, sy = w-1-x, h-1-y
+	case 4: // mirrored upside down
+		sx, sy = x, h-1-y
+	case 5: // mirrored and on its left side
+		sx, sy = y, x
+	case 6: // on its left side, turned clockwise
+		sx, sy = y, h-1-x
+	case 7: // mirrored and on its right side
+		sx, sy = w-1-y, h-1-x
+	case 8: // on its right side, turned counterclockwise
+		sx, sy = w-1-y, x
+	default:
+		sx, sy = x, y
+	}
+	return o.Image.At(bounds.Min.X+sx, bounds.Min.Y+sy)
+}
+
+// orientation returns the EXIF orientation of the JPEG in data, zero when it has none
+func orientation(data []byte) int {
+	const (
+		markerSOS      = 0xda
+		markerAPP1     = 0xe1
+		tagOrientation = 0x0112
+	)
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return 0
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == markerSOS || length < 2 || i+2+length > len(data) {
+			return 0
+		}
+		segment := data[i+4 : i+2+length]
+		i += 2 + length
+		if marker != markerAPP1 || !bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			continue
+		}
+
+		tiff := segment[6:]
+		if len(tiff) < 8 {
+			return 0
+		}
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 0
+		}
+		ifd := int(order.Uint32(tiff[4:]))
+		if ifd < 8 || ifd+2 > len(tiff) {
+			return 0
+		}
+		entries := int(order.Uint16(tiff[ifd:]))
+		for e := 0; e < entries; e++ {
+			entry := ifd + 2 + e*12
+			if entry+12 > len(tiff) {
+				return 0
+			}
+			if order.Uint16(tiff[entry:]) == tagOrientation {
+				return int(order.Uint16(tiff[entry+8:]))
+			}
+		}
+		return 0
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.RGBA{R: 0xff, A: 0xff}
+	blue = color.RGBA{B: 0xff, A: 0xff}
+)
+
+// halves is a w by h picture, red on the left half and blue on the right one
+func halves(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.SetRGBA(x, y, red)
+			} else {
+				img.SetRGBA(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+// withOrientation inserts an EXIF segment recording orientation o after the start of the JPEG
+func withOrientation(t *testing.T, data []byte, o uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry, 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], o)
+	segment := append(append([]byte("Exif\x00\x00"), tiff...), entry...)
+	segment = append(segment, 0, 0, 0, 0) // no next IFD
+
+	header := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(segment)+2))
+	require.Equal(t, []byte{0xff, 0xd8}, data[:2])
+	return append(append(append([]byte{0xff, 0xd8}, header...), segment...), data[2:]...)
+}
+
+func near(t *testing.T, want color.RGBA, got color.Color) {
+	r, g, b, _ := got.RGBA()
+	require.InDelta(t, want.R, r>>8, 40)
+	require.InDelta(t, want.G, g>>8, 40)
+	require.InDelta(t, want.B, b>>8, 40)
+}
+
+func Test_Square(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, halves(40, 20)))
+	img, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+
+	// the centered square keeps half of each color
+	thumb := Square(img, 10)
+	require.Equal(t, image.Rect(0, 0, 10, 10), thumb.Bounds())
+	require.Equal(t, red, thumb.RGBAAt(0, 5))
+	require.Equal(t, blue, thumb.RGBAAt(9, 5))
+
+	// smaller pictures are scaled up
+	require.Equal(t, blue, Square(img, 64).RGBAAt(63, 63))
+
+	// transparency is put on white
+	transparent := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	require.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, Square(transparent, 2).RGBAAt(1, 1))
+}
+
+func Test_Decode(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, halves(40, 20), &jpeg.Options{Quality: 100}))
+
+	// on its left side: the picture turns clockwise, red ends up on top
+	img, err := Decode(withOrientation(t, buf.Bytes(), 6))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+	near(t, red, img.At(10, 2))
+	near(t, blue, img.At(10, 37))
+
+	img, err = Decode(withOrientation(t, buf.Bytes(), 3))
+	require.NoError(t, err)
+	near(t, blue, img.At(2, 10))
+
+	// the thumbnail carries no EXIF
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, Square(img, 8)))
+	require.NotContains(t, out.String(), "Exif")
+
+	_, err = Decode([]byte("GIF89a"))
+	require.ErrorIs(t, err, ErrUnsupported)
+
+	var huge bytes.Buffer
+	require.NoError(t, png.Encode(&huge, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := huge.Bytes()
+	// IHDR holds the width and height right after the signature and chunk header
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	_, err = Decode(data)
+	require.ErrorIs(t, err, ErrTooLarge)
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+var (
+	// stringLiterals and numberLiterals are replaced so values written into raw SQL never reach a span
+	stringLiterals = regexp.MustCompile(`N?'(?:[^']|'')*'`)
+	numberLiterals = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+)
+
+type gormPlugin struct{}
+
+var _ gorm.Plugin = gormPlugin{}
+
+// Gorm returns a gorm plugin creating a client span per statement, use it with db.Use
+func Gorm() gorm.Plugin {
+	return gormPlugin{}
+}
+
+// Name of the gorm plugin
+func (gormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the callbacks around every gorm operation
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", before("create")),
+		cb.Create().After("*").Register("tracing:after_create", after),
+		cb.Query().Before("*").Register("tracing:before_query", before("query")),
+		cb.Query().After("*").Register("tracing:after_query", after),
+		cb.Update().Before("*").Register("tracing:before_update", before("update")),
+		cb.Update().After("*").Register("tracing:after_update", after),
+		cb.Delete().Before("*").Register("tracing:before_delete", before("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", after),
+		cb.Row().Before("*").Register("tracing:before_row", before("row")),
+		cb.Row().After("*").Register("tracing:after_row", after),
+		cb.Raw().Before("*").Register("tracing:before_raw", before("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", after),
+	)
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(dbSystem(db.Dialector)),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// dbSystem names the database behind a gorm dialector
+func dbSystem(dialector gorm.Dialector) attribute.KeyValue {
+	switch dialector.Name() {
+	case "postgres":
+		return semconv.DBSystemPostgreSQL
+	case "sqlite":
+		return semconv.DBSystemSqlite
+	default:
+		return semconv.DBSystemMSSQL
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBSQLTable(db.Statement.Table))
+	}
+	if sql := db.Statement.SQL.String(); sql != "" {
+		span.SetAttributes(semconv.DBStatement(Sanitize(sql)))
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
+
+// Sanitize strips literal values from SQL. Statements built by gorm already use @pN placeholders,
+// this covers raw SQL with inlined values.
+func Sanitize(sql string) string {
+	sql = stringLiterals.ReplaceAllString(sql, "?")
+	sql = numberLiterals.ReplaceAllString(sql, "?")
+	return strings.Join(strings.Fields(sql), " ")
+}
@@ -0,0 +1,87 @@
+// Package tracing sets up OpenTelemetry and instruments gin, gqlgen and gorm with spans
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"employee-management-system/pkg/config"
+)
+
+// instrumentationName identifies the spans created by this package
+const instrumentationName = "employee-management-system/pkg/tracing"
+
+// Setup installs the global tracer provider and the W3C trace context propagator. The returned
+// function flushes buffered spans and must be called before the process exits.
+func Setup(ctx context.Context, cfg config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case config.TracingExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		exporter = e
+	case config.TracingExporterOTLP:
+		e, err := otlptracehttp.New(ctx, otlpOptions(cfg)...)
+		if err != nil {
+			return nil, err
+		}
+		exporter = e
+	default:
+		// spans are still created so the trace context is propagated, they are just not recorded
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// otlpOptions accepts the collector either as host:port or, like OTEL_EXPORTER_OTLP_ENDPOINT
+// usually is, as a URL whose scheme decides on TLS
+func otlpOptions(cfg config.Tracing) []otlptracehttp.Option {
+	endpoint, insecure := cfg.Endpoint, cfg.Insecure
+	if u, err := url.Parse(cfg.Endpoint); err == nil && u.Host != "" {
+		endpoint = u.Host
+		insecure = insecure || u.Scheme == "http"
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	return options
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
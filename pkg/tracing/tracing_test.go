@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+
+	"employee-management-system/pkg/gorm_sqlmock"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func Test_HTTP_ContinuesTraceContext(t *testing.T) {
+	recorder := recordSpans(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(HTTP())
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /users/:id", spans[0].Name())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	require.Equal(t, "Error", spans[0].Status().Code.String())
+}
+
+func Test_Gorm(t *testing.T) {
+	recorder := recordSpans(t)
+	db, mock, err := gorm_sqlmock.New(gorm_sqlmock.Config{
+		Config:     &gorm.Config{},
+		DriverName: "sqlserver",
+		DSN:        "mock",
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Gorm()))
+
+	type user struct{ ID int }
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	require.NoError(t, db.Where("id = ?", 42).Find(&[]user{}).Error)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "gorm.query", spans[0].Name())
+	for _, attr := range spans[0].Attributes() {
+		if attr.Key == "db.statement" {
+			require.NotContains(t, attr.Value.AsString(), "42")
+		}
+	}
+}
+
+func Test_Sanitize(t *testing.T) {
+	require.Equal(t,
+		"SELECT * FROM users WHERE user_name = ? AND id > ? AND token = @p1",
+		Sanitize("SELECT *  FROM users\n WHERE user_name = N'o''brien' AND id > 10 AND token = @p1"),
+	)
+}
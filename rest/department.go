@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"employee-management-system/model"
+)
+
+type (
+	// Department representation
+	Department struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// DepartmentList holds every department
+	DepartmentList struct {
+		Data []Department `json:"data"`
+	}
+
+	// DepartmentRequest body of create and update
+	DepartmentRequest struct {
+		Name string `json:"name" binding:"required,max=50"`
+	}
+)
+
+func toDepartment(d model.Department) Department {
+	return Department{
+		ID:   d.ID,
+		Name: d.DepartmentName,
+	}
+}
+
+func (h *Handler) listDepartments(c *gin.Context) {
+	departments, err := h.controller.GetAllDepartments(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	list := DepartmentList{Data: make([]Department, 0, len(departments))}
+	for _, department := range departments {
+		list.Data = append(list.Data, toDepartment(*department))
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) getDepartment(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	department, err := h.controller.GetDepartmentByID(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toDepartment(department))
+}
+
+func (h *Handler) createDepartment(c *gin.Context) {
+	var req DepartmentRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	department, err := h.controller.AddDepartment(c, model.Department{DepartmentName: req.Name})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Header("Location", c.FullPath()+"/"+strconv.Itoa(department.ID))
+	c.JSON(http.StatusCreated, toDepartment(department))
+}
+
+func (h *Handler) updateDepartment(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	var req DepartmentRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	department, err := h.controller.UpdateDepartmentByID(c, id, model.Department{DepartmentName: req.Name})
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toDepartment(department))
+}
+
+func (h *Handler) deleteDepartment(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if err := h.controller.DeleteDepartmentByID(c, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
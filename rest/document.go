@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"employee-management-system/pkg/apierror"
+)
+
+// downloadDocument serves the content of a document to a URL the GraphQL API signed, the signature
+// authorizes the request in place of a signed in caller
+func (h *Handler) downloadDocument(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	tenantID, err := strconv.Atoi(c.Query("tenant"))
+	if err != nil {
+		h.writeError(c, apierror.BadInput("tenant must be an integer"))
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		h.writeError(c, apierror.BadInput("expires must be an integer"))
+		return
+	}
+
+	document, content, err := h.controller.OpenDocument(c, id, tenantID, expires, c.Query("signature"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, document.Size, document.ContentType, content, nil)
+}
@@ -0,0 +1,343 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/apierror"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	maxPageSize = 100
+)
+
+type (
+	// Employee representation
+	Employee struct {
+		ID           int       `json:"id"`
+		UserID       int       `json:"userId"`
+		FirstName    string    `json:"firstName"`
+		LastName     string    `json:"lastName"`
+		Email        string    `json:"email"`
+		Dob          string    `json:"dob" format:"date"`
+		DepartmentID *int      `json:"departmentId"`
+		Position     string    `json:"position"`
+		UpdatedAt    time.Time `json:"updatedAt"`
+	}
+
+	// EmployeeList is a page of employees
+	EmployeeList struct {
+		Data []Employee `json:"data"`
+		Page PageInfo   `json:"page"`
+	}
+
+	// PageInfo describes the page returned and how many records match in total
+	PageInfo struct {
+		Number          int   `json:"number"`
+		Size            int   `json:"size"`
+		TotalCount      int64 `json:"totalCount"`
+		HasNextPage     bool  `json:"hasNextPage"`
+		HasPreviousPage bool  `json:"hasPreviousPage"`
+	}
+
+	// CreateEmployeeRequest body
+	CreateEmployeeRequest struct {
+		UserID       int    `json:"userId" binding:"required"`
+		FirstName    string `json:"firstName" binding:"required"`
+		LastName     string `json:"lastName" binding:"required"`
+		Email        string `json:"email" binding:"required,email"`
+		Dob          string `json:"dob" binding:"required" format:"date"`
+		DepartmentID *int   `json:"departmentId,omitempty"`
+		Position     string `json:"position,omitempty"`
+	}
+
+	// UpdateEmployeeRequest body, omitted fields are left unchanged
+	UpdateEmployeeRequest struct {
+		FirstName    *string `json:"firstName,omitempty"`
+		LastName     *string `json:"lastName,omitempty"`
+		Dob          *string `json:"dob,omitempty" format:"date"`
+		DepartmentID *int    `json:"departmentId,omitempty"`
+		Position     *string `json:"position,omitempty"`
+	}
+)
+
+func toEmployee(e model.Employee) Employee {
+	employee := Employee{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		FirstName: e.FirstName,
+		LastName:  e.LastName,
+		Email:     e.Email,
+		Position:  e.Position,
+		UpdatedAt: e.UpdatedAt,
+	}
+	if !e.Dob.IsZero() {
+		employee.Dob = e.Dob.Format(dateLayout)
+	}
+	if e.DepartmentID != 0 {
+		departmentID := e.DepartmentID
+		employee.DepartmentID = &departmentID
+	}
+	return employee
+}
+
+func parseDate(field, value string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, apierror.BadInput("%s must be a date formatted YYYY-MM-DD", field)
+	}
+	return t, nil
+}
+
+// queryInt parses an optional positive integer query parameter
+func queryInt(c *gin.Context, name string) (*int, error) {
+	value, ok := c.GetQuery(name)
+	if !ok || value == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		return nil, apierror.BadInput("%s must be a positive integer", name)
+	}
+	return &i, nil
+}
+
+func (h *Handler) listEmployees(c *gin.Context) {
+	var (
+		filter model.EmployeeFilter
+		page   pagination.Page
+		err    error
+	)
+	if filter.DepartmentID, err = queryInt(c, "departmentId"); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	if position, ok := c.GetQuery("position"); ok {
+		filter.Position = &position
+	}
+	if search, ok := c.GetQuery("q"); ok {
+		filter.Search = &search
+	}
+
+	if page.Number, err = queryInt(c, "page"); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	if page.Size, err = queryInt(c, "size"); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	if page.Size != nil && *page.Size > maxPageSize {
+		h.writeError(c, apierror.BadInput("size must not exceed %d", maxPageSize))
+		return
+	}
+	sortBy := c.DefaultQuery("sort", "id")
+	page.SortBy = &sortBy
+	desc := c.Query("order") == pagination.PageSortDirectionDescending
+	page.SortDirectionDesc = &desc
+
+	employees, info, err := h.controller.ListEmployees(c, filter, page)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	list := EmployeeList{
+		Data: make([]Employee, 0, len(employees)),
+		Page: PageInfo{
+			Number:          info.Page,
+			Size:            info.Size,
+			TotalCount:      info.TotalCount,
+			HasNextPage:     info.HasNextPage,
+			HasPreviousPage: info.HasPreviousPage,
+		},
+	}
+	for _, employee := range employees {
+		list.Data = append(list.Data, toEmployee(*employee))
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) getEmployee(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	employee, err := h.controller.GetEmployeeByID(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toEmployee(employee))
+}
+
+func (h *Handler) createEmployee(c *gin.Context) {
+	var req CreateEmployeeRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	dob, err := parseDate("dob", req.Dob)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	employee := model.Employee{
+		UserID:    req.UserID,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Dob:       dob,
+		Position:  req.Position,
+	}
+	if req.DepartmentID != nil {
+		employee.DepartmentID = *req.DepartmentID
+	}
+
+	employee, err = h.controller.AddEmployee(c, employee)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Header("Location", c.FullPath()+"/"+strconv.Itoa(employee.ID))
+	c.JSON(http.StatusCreated, toEmployee(employee))
+}
+
+func (h *Handler) updateEmployee(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	var req UpdateEmployeeRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	if _, err := h.controller.GetEmployeeByID(c, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	// zero values are not written, so only the supplied fields change
+	var changes model.Employee
+	if req.FirstName != nil {
+		changes.FirstName = *req.FirstName
+	}
+	if req.LastName != nil {
+		changes.LastName = *req.LastName
+	}
+	if req.Dob != nil {
+		if changes.Dob, err = parseDate("dob", *req.Dob); err != nil {
+			h.writeError(c, err)
+			return
+		}
+	}
+	if req.DepartmentID != nil {
+		changes.DepartmentID = *req.DepartmentID
+	}
+	if req.Position != nil {
+		changes.Position = *req.Position
+	}
+
+	if _, err := h.controller.UpdateEmployeeByID(c, id, changes); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	employee, err := h.controller.GetEmployeeByID(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toEmployee(employee))
+}
+
+func (h *Handler) deleteEmployee(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	if _, err := h.controller.GetEmployeeByID(c, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if err := h.controller.DeleteEmployeeByID(c, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) exportPersonalData(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	data, err := h.controller.ExportPersonalData(c, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="personal-data-%d.json"`, id))
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) erasePersonalData(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if err := h.controller.ErasePersonalData(c, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// getProfilePhoto serves a thumbnail to a URL the GraphQL API signed. The URL names one version of
+// the photo, so it is cached for good and revalidated by its ETag.
+func (h *Handler) getProfilePhoto(c *gin.Context) {
+	id, err := pathID(c)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	tenantID, err := strconv.Atoi(c.Query("tenant"))
+	if err != nil {
+		h.writeError(c, apierror.BadInput("tenant must be an integer"))
+		return
+	}
+	version, size := c.Query("version"), model.PhotoSize(c.Query("size"))
+
+	content, err := h.controller.OpenProfilePhoto(c, id, tenantID, version, size, c.Query("signature"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	defer content.Close()
+
+	etag := fmt.Sprintf(`"%s-%s"`, version, size)
+	c.Header("Cache-Control", "private, max-age=31536000, immutable")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, "image/jpeg", content, nil)
+}
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const openAPIVersion = "3.0.3"
+
+// object is a JSON object of the OpenAPI document
+type object = map[string]interface{}
+
+// OpenAPI builds the OpenAPI 3 document of routes. Schemas are derived from the request and response
+// types: a field is optional when it is a pointer or tagged omitempty, and a `format` tag sets its format.
+func OpenAPI(routes []route) object {
+	g := &schemaGenerator{schemas: object{}}
+	errorResponse := object{
+		"description": "Error",
+		"content":     object{"application/json": object{"schema": g.schema(reflect.TypeOf(ErrorResponse{}))}},
+	}
+
+	paths := object{}
+	for _, r := range routes {
+		operation := object{
+			"operationId": r.operationID,
+			"summary":     r.summary,
+			"tags":        []string{r.tag},
+			"security":    []object{{"bearerAuth": []string{}}, {"apiKey": []string{}}},
+		}
+		if r.anonymous {
+			operation["security"] = []object{}
+		}
+
+		if len(r.params) > 0 {
+			params := make([]object, 0, len(r.params))
+			for _, p := range r.params {
+				param := object{
+					"name":     p.name,
+					"in":       p.in,
+					"required": p.in == "path",
+					"schema":   object{"type": p.typ},
+				}
+				if p.description != "" {
+					param["description"] = p.description
+				}
+				params = append(params, param)
+			}
+			operation["parameters"] = params
+		}
+
+		if r.body != nil {
+			operation["requestBody"] = object{
+				"required": true,
+				"content":  object{"application/json": object{"schema": g.schema(reflect.TypeOf(r.body))}},
+			}
+		}
+
+		success := object{"description": http.StatusText(r.status)}
+		if r.response != nil {
+			success["content"] = object{"application/json": object{"schema": g.schema(reflect.TypeOf(r.response))}}
+		}
+		operation["responses"] = object{
+			strconv.Itoa(r.status): success,
+			"default":              errorResponse,
+		}
+
+		path := openAPIPath(r.path)
+		item, ok := paths[path].(object)
+		if !ok {
+			item = object{}
+			paths[path] = item
+		}
+		item[strings.ToLower(r.method)] = operation
+	}
+
+	return object{
+		"openapi": openAPIVersion,
+		"info": object{
+			"title":   "Employee Management System",
+			"version": "1.0.0",
+		},
+		"servers": []object{{"url": "/api/v1"}},
+		"paths":   paths,
+		"components": object{
+			"schemas": g.schemas,
+			"securitySchemes": object{
+				"bearerAuth": object{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKey":     object{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+}
+
+// openAPIPath turns gin parameters such as :id into {id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// schemaGenerator collects named struct schemas as components
+type schemaGenerator struct {
+	schemas object
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGenerator) schema(t reflect.Type) object {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return object{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		if _, ok := g.schemas[t.Name()]; !ok {
+			// reserve the name first so recursive types terminate
+			g.schemas[t.Name()] = object{}
+			g.schemas[t.Name()] = g.structSchema(t)
+		}
+		return object{"$ref": "#/components/schemas/" + t.Name()}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return object{"type": "array", "items": g.schema(t.Elem())}
+	case t.Kind() == reflect.String:
+		return object{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return object{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return object{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return object{"type": "number"}
+	default:
+		return object{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) object {
+	properties := object{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+		if format := field.Tag.Get("format"); format != "" {
+			property["format"] = format
+		}
+		properties[name] = property
+
+		if field.Type.Kind() != reflect.Pointer && !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,108 @@
+// Package rest exposes a plain JSON HTTP API next to GraphQL for integrations that would rather not
+// speak GraphQL. It shares controller.Operations, authentication and error codes with the GraphQL API.
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/middleware"
+)
+
+const packageName = "rest"
+
+type (
+	// Handler serves the REST API
+	Handler struct {
+		logger     zerolog.Logger
+		controller controller.Operations
+	}
+
+	// ErrorResponse has the shape of a GraphQL error response so clients can share error handling
+	ErrorResponse struct {
+		Errors []ErrorItem `json:"errors"`
+	}
+
+	// ErrorItem describes one error
+	ErrorItem struct {
+		Message    string          `json:"message"`
+		Extensions ErrorExtensions `json:"extensions"`
+	}
+
+	// ErrorExtensions carries the API error code
+	ErrorExtensions struct {
+		Code string `json:"code"`
+	}
+)
+
+// New Handler calling c
+func New(z zerolog.Logger, c controller.Operations) *Handler {
+	return &Handler{
+		logger:     z.With().Str(helper.LogStrKeyModule, packageName).Logger(),
+		controller: c,
+	}
+}
+
+// Register adds the API routes and the OpenAPI document to g. Authentication is expected to have run
+// already, every route but the document and the anonymous ones requires a signed in caller.
+func (h *Handler) Register(g *gin.RouterGroup) {
+	document := OpenAPI(h.routes())
+	g.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, document)
+	})
+
+	for _, r := range h.routes() {
+		if r.anonymous {
+			g.Handle(r.method, r.path, r.handler)
+			continue
+		}
+		g.Handle(r.method, r.path, h.requireUser, r.handler)
+	}
+}
+
+func (h *Handler) requireUser(c *gin.Context) {
+	if _, err := middleware.UserFromContext(c.Request.Context()); err != nil {
+		h.writeError(c, err)
+	}
+}
+
+// writeError answers with the status and code the API maps err to
+func (h *Handler) writeError(c *gin.Context, err error) {
+	code := apierror.Code(err)
+	status := apierror.Status(code)
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		logging.Method(c, h.logger, "writeError").Err(err).Msgf("Handler::writeError %s %s: %v", c.Request.Method, c.FullPath(), err)
+		message = http.StatusText(status)
+	}
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Errors: []ErrorItem{{
+			Message:    message,
+			Extensions: ErrorExtensions{Code: code},
+		}},
+	})
+}
+
+// pathID parses the :id path parameter
+func pathID(c *gin.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		return 0, apierror.BadInput("id must be a positive integer")
+	}
+	return id, nil
+}
+
+// bindJSON decodes the request body into v
+func bindJSON(c *gin.Context, v interface{}) error {
+	if err := c.ShouldBindJSON(v); err != nil {
+		return apierror.BadInput("invalid request body: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+// fakeOperations implements the operations the handlers call, the embedded interface panics on anything else
+type fakeOperations struct {
+	controller.Operations
+	employees map[int]model.Employee
+	filter    model.EmployeeFilter
+	page      pagination.Page
+}
+
+func (f *fakeOperations) GetEmployeeByID(_ context.Context, id int) (model.Employee, error) {
+	employee, ok := f.employees[id]
+	if !ok {
+		return model.Employee{}, storage.ErrRecordNotFound
+	}
+	return employee, nil
+}
+
+func (f *fakeOperations) ListEmployees(_ context.Context, filter model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error) {
+	f.filter, f.page = filter, page
+	employee := f.employees[1]
+	return []*model.Employee{&employee}, pagination.PageInfo{Page: *page.Number, Size: *page.Size, TotalCount: 1}, nil
+}
+
+func (f *fakeOperations) DeleteEmployeeByID(_ context.Context, id int) error {
+	return fmt.Errorf("database is down")
+}
+
+func (f *fakeOperations) ExportPersonalData(_ context.Context, employeeID int) (model.PersonalData, error) {
+	employee, ok := f.employees[employeeID]
+	if !ok {
+		return model.PersonalData{}, storage.ErrRecordNotFound
+	}
+	return model.PersonalData{Employee: model.PersonalEmployee{ID: employee.ID, FirstName: employee.FirstName}}, nil
+}
+
+func (f *fakeOperations) OpenDocument(_ context.Context, id, tenantID int, expires int64, signature string) (model.Document, io.ReadCloser, error) {
+	if id != 1 || tenantID != 1 || expires != 1700000000 || signature != "valid" {
+		return model.Document{}, nil, storage.ErrUnauthorizedAccess
+	}
+	return model.Document{ID: 1, FileName: "contract.pdf", ContentType: "application/pdf", Size: 8}, io.NopCloser(strings.NewReader("%PDF-1.4")), nil
+}
+
+func (f *fakeOperations) OpenProfilePhoto(_ context.Context, employeeID, tenantID int, version string, size model.PhotoSize, signature string) (io.ReadCloser, error) {
+	if employeeID != 1 || tenantID != 1 || version != "v1" || signature != "valid" {
+		return nil, storage.ErrUnauthorizedAccess
+	}
+	return io.NopCloser(strings.NewReader("jpeg " + string(size))), nil
+}
+
+func newRouter(ops controller.Operations, user *model.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// as the server does, handlers pass the gin context on to the controller
+	r.ContextWithFallback = true
+	g := r.Group("/api/v1", func(c *gin.Context) {
+		if user != nil {
+			c.Request = c.Request.WithContext(middleware.ContextWithUser(c.Request.Context(), user))
+		}
+	})
+	New(zerolog.Nop(), ops).Register(g)
+	return r
+}
+
+func serve(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 1)
+	return body.Errors[0].Extensions.Code
+}
+
+func Test_Handler_Errors(t *testing.T) {
+	ops := &fakeOperations{employees: map[int]model.Employee{1: {ID: 1, FirstName: "Jane"}}}
+
+	w := serve(newRouter(ops, nil), http.MethodGet, "/api/v1/employees/1")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Equal(t, "UNAUTHENTICATED", errorCode(t, w))
+
+	r := newRouter(ops, &model.User{ID: 7})
+	w = serve(r, http.MethodGet, "/api/v1/employees/2")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "NOT_FOUND", errorCode(t, w))
+
+	w = serve(r, http.MethodGet, "/api/v1/employees/abc")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "BAD_USER_INPUT", errorCode(t, w))
+
+	// internal failures are not leaked to the client
+	w = serve(r, http.MethodDelete, "/api/v1/employees/1")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, "INTERNAL_SERVER_ERROR", errorCode(t, w))
+	require.NotContains(t, w.Body.String(), "database is down")
+
+	w = serve(r, http.MethodGet, "/api/v1/employees/1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"firstName":"Jane"`)
+}
+
+func Test_Handler_ExportPersonalData(t *testing.T) {
+	ops := &fakeOperations{employees: map[int]model.Employee{1: {ID: 1, FirstName: "Jane"}}}
+	r := newRouter(ops, &model.User{ID: 7, Kind: model.KindAdministrator})
+
+	w := serve(r, http.MethodGet, "/api/v1/employees/1/personal-data")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `attachment; filename="personal-data-1.json"`, w.Header().Get("Content-Disposition"))
+	require.Contains(t, w.Body.String(), `"first_name":"Jane"`)
+
+	w = serve(r, http.MethodGet, "/api/v1/employees/2/personal-data")
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func Test_Handler_DownloadDocument(t *testing.T) {
+	// signed URLs need no caller
+	r := newRouter(&fakeOperations{}, nil)
+
+	w := serve(r, http.MethodGet, "/api/v1/documents/1/content?tenant=1&expires=1700000000&signature=valid")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	require.Equal(t, `attachment; filename=contract.pdf`, w.Header().Get("Content-Disposition"))
+	require.Equal(t, "%PDF-1.4", w.Body.String())
+
+	w = serve(r, http.MethodGet, "/api/v1/documents/1/content?tenant=1&expires=1700000000&signature=forged")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(r, http.MethodGet, "/api/v1/documents/1/content?tenant=1")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_Handler_GetProfilePhoto(t *testing.T) {
+	r := newRouter(&fakeOperations{}, nil)
+
+	w := serve(r, http.MethodGet, "/api/v1/employees/1/photo?size=small&tenant=1&version=v1&signature=valid")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	require.Equal(t, "private, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	require.Equal(t, `"v1-small"`, w.Header().Get("ETag"))
+	require.Equal(t, "jpeg small", w.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/employees/1/photo?size=small&tenant=1&version=v1&signature=valid", nil)
+	req.Header.Set("If-None-Match", `"v1-small"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotModified, w.Code)
+	require.Empty(t, w.Body.String())
+
+	w = serve(r, http.MethodGet, "/api/v1/employees/1/photo?size=small&tenant=1&version=v0&signature=valid")
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func Test_Handler_DepartmentWrites(t *testing.T) {
+	// the real controller, its authorization rejects staff before any query is made
+	mock, s := storage.GetStorage(t)
+	cfg := config.Default()
+	s.Config = &cfg
+	r := newRouter(*controller.New(zerolog.Nop(), s, nil), &model.User{ID: 7, Kind: model.KindStaff})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/v1/departments", strings.NewReader(`{"name":"Sales"}`)),
+		httptest.NewRequest(http.MethodPatch, "/api/v1/departments/1", strings.NewReader(`{"name":"Sales"}`)),
+		httptest.NewRequest(http.MethodDelete, "/api/v1/departments/1", nil),
+	} {
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code, req.Method)
+		require.Equal(t, "FORBIDDEN", errorCode(t, w))
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_Handler_ListEmployees(t *testing.T) {
+	ops := &fakeOperations{employees: map[int]model.Employee{1: {ID: 1, DepartmentID: 3}}}
+	r := newRouter(ops, &model.User{ID: 7})
+
+	w := serve(r, http.MethodGet, "/api/v1/employees?page=2&size=5&sort=lastName&order=desc&departmentId=3&q=jan")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 2, *ops.page.Number)
+	require.Equal(t, 5, *ops.page.Size)
+	require.Equal(t, "lastName", *ops.page.SortBy)
+	require.True(t, *ops.page.SortDirectionDesc)
+	require.Equal(t, 3, *ops.filter.DepartmentID)
+	require.Equal(t, "jan", *ops.filter.Search)
+	require.Nil(t, ops.filter.Position)
+
+	var list EmployeeList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	require.Equal(t, 3, *list.Data[0].DepartmentID)
+	require.Equal(t, PageInfo{Number: 2, Size: 5, TotalCount: 1}, list.Page)
+
+	w = serve(r, http.MethodGet, "/api/v1/employees?size=500")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_OpenAPI(t *testing.T) {
+	w := serve(newRouter(&fakeOperations{}, nil), http.MethodGet, "/api/v1/openapi.json")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var document struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+				Required   []string                          `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+	require.True(t, strings.HasPrefix(document.OpenAPI, "3."))
+
+	require.Contains(t, document.Paths, "/employees")
+	require.Contains(t, document.Paths["/employees/{id}"], "patch")
+	require.Contains(t, document.Paths["/departments/{id}"], "delete")
+
+	employee := document.Components.Schemas["Employee"]
+	require.Equal(t, "date", employee.Properties["dob"]["format"])
+	require.Equal(t, "date-time", employee.Properties["updatedAt"]["format"])
+	require.NotContains(t, employee.Required, "departmentId")
+	require.Contains(t, employee.Required, "email")
+	require.Empty(t, document.Components.Schemas["UpdateEmployeeRequest"].Required)
+	require.Contains(t, document.Components.Schemas, "ErrorResponse")
+	require.Equal(t, "#/components/schemas/Employee",
+		document.Components.Schemas["EmployeeList"].Properties["data"]["items"].(map[string]interface{})["$ref"])
+}
@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"employee-management-system/model"
+)
+
+type (
+	// route describes one endpoint, for both the router and the OpenAPI document
+	route struct {
+		method      string
+		path        string
+		operationID string
+		summary     string
+		tag         string
+		params      []param
+		body        interface{}
+		status      int
+		response    interface{}
+		handler     gin.HandlerFunc
+		// anonymous routes authorize the request themselves instead of requiring a signed in caller
+		anonymous bool
+	}
+
+	// param is a path or query parameter
+	param struct {
+		name        string
+		in          string
+		typ         string
+		description string
+	}
+)
+
+var (
+	idParam = param{name: "id", in: "path", typ: "integer"}
+
+	pageParams = []param{
+		{name: "page", in: "query", typ: "integer", description: "page number, starting at 1"},
+		{name: "size", in: "query", typ: "integer", description: "page size, at most 100"},
+		{name: "sort", in: "query", typ: "string", description: "id, firstName, lastName or position"},
+		{name: "order", in: "query", typ: "string", description: "asc or desc"},
+	}
+)
+
+func (h *Handler) routes() []route {
+	return []route{
+		{
+			method: http.MethodGet, path: "/employees", operationID: "listEmployees", tag: "employees",
+			summary: "List employees",
+			params: append([]param{
+				{name: "departmentId", in: "query", typ: "integer"},
+				{name: "position", in: "query", typ: "string"},
+				{name: "q", in: "query", typ: "string", description: "matches first name or last name containing it, or for administrators the whole email address"},
+			}, pageParams...),
+			status: http.StatusOK, response: EmployeeList{}, handler: h.listEmployees,
+		},
+		{
+			method: http.MethodGet, path: "/employees/:id", operationID: "getEmployee", tag: "employees",
+			summary: "Get an employee", params: []param{idParam},
+			status: http.StatusOK, response: Employee{}, handler: h.getEmployee,
+		},
+		{
+			method: http.MethodPost, path: "/employees", operationID: "createEmployee", tag: "employees",
+			summary: "Create an employee", body: CreateEmployeeRequest{},
+			status: http.StatusCreated, response: Employee{}, handler: h.createEmployee,
+		},
+		{
+			method: http.MethodPatch, path: "/employees/:id", operationID: "updateEmployee", tag: "employees",
+			summary: "Update the given fields of an employee", params: []param{idParam}, body: UpdateEmployeeRequest{},
+			status: http.StatusOK, response: Employee{}, handler: h.updateEmployee,
+		},
+		{
+			method: http.MethodDelete, path: "/employees/:id", operationID: "deleteEmployee", tag: "employees",
+			summary: "Delete an employee", params: []param{idParam},
+			status: http.StatusNoContent, handler: h.deleteEmployee,
+		},
+		{
+			method: http.MethodGet, path: "/employees/:id/personal-data", operationID: "exportPersonalData", tag: "employees",
+			summary: "Download everything held about an employee and its user as a JSON archive", params: []param{idParam},
+			status: http.StatusOK, response: model.PersonalData{}, handler: h.exportPersonalData,
+		},
+		{
+			method: http.MethodDelete, path: "/employees/:id/personal-data", operationID: "erasePersonalData", tag: "employees",
+			summary: "Anonymize the personal data of an employee and its user, keeping leave and the audit trail", params: []param{idParam},
+			status: http.StatusNoContent, handler: h.erasePersonalData,
+		},
+		{
+			method: http.MethodGet, path: "/documents/:id/content", operationID: "downloadDocument", tag: "documents",
+			summary: "Download the content of an employee document through a signed URL of the GraphQL API",
+			params: []param{
+				idParam,
+				{name: "tenant", in: "query", typ: "integer"},
+				{name: "expires", in: "query", typ: "integer", description: "Unix time the URL expires at"},
+				{name: "signature", in: "query", typ: "string"},
+			},
+			status: http.StatusOK, handler: h.downloadDocument, anonymous: true,
+		},
+		{
+			method: http.MethodGet, path: "/employees/:id/photo", operationID: "getProfilePhoto", tag: "employees",
+			summary: "Get the profile photo of an employee as a square JPEG through a signed URL of the GraphQL API",
+			params: []param{
+				idParam,
+				{name: "size", in: "query", typ: "string", description: "small, medium or large"},
+				{name: "tenant", in: "query", typ: "integer"},
+				{name: "version", in: "query", typ: "string"},
+				{name: "signature", in: "query", typ: "string"},
+			},
+			status: http.StatusOK, handler: h.getProfilePhoto, anonymous: true,
+		},
+		{
+			method: http.MethodGet, path: "/departments", operationID: "listDepartments", tag: "departments",
+			summary: "List departments",
+			status:  http.StatusOK, response: DepartmentList{}, handler: h.listDepartments,
+		},
+		{
+			method: http.MethodGet, path: "/departments/:id", operationID: "getDepartment", tag: "departments",
+			summary: "Get a department", params: []param{idParam},
+			status: http.StatusOK, response: Department{}, handler: h.getDepartment,
+		},
+		{
+			method: http.MethodPost, path: "/departments", operationID: "createDepartment", tag: "departments",
+			summary: "Create a department", body: DepartmentRequest{},
+			status: http.StatusCreated, response: Department{}, handler: h.createDepartment,
+		},
+		{
+			method: http.MethodPatch, path: "/departments/:id", operationID: "updateDepartment", tag: "departments",
+			summary: "Rename a department", params: []param{idParam}, body: DepartmentRequest{},
+			status: http.StatusOK, response: Department{}, handler: h.updateDepartment,
+		},
+		{
+			method: http.MethodDelete, path: "/departments/:id", operationID: "deleteDepartment", tag: "departments",
+			summary: "Delete a department", params: []param{idParam},
+			status: http.StatusNoContent, handler: h.deleteDepartment,
+		},
+	}
+}
@@ -0,0 +1,198 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/rpc/employeepb"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// listBatchSize is the number of employees read from the database per page while streaming
+	listBatchSize = 100
+)
+
+// employeeService implements employeepb.EmployeeServiceServer
+type employeeService struct {
+	employeepb.UnimplementedEmployeeServiceServer
+	controller controller.Operations
+}
+
+func toEmployee(e model.Employee) *employeepb.Employee {
+	employee := &employeepb.Employee{
+		Id:           int64(e.ID),
+		UserId:       int64(e.UserID),
+		FirstName:    e.FirstName,
+		LastName:     e.LastName,
+		Email:        e.Email,
+		DepartmentId: int64(e.DepartmentID),
+		Position:     e.Position,
+		UpdatedAt:    timestamppb.New(e.UpdatedAt),
+	}
+	if !e.Dob.IsZero() {
+		employee.Dob = e.Dob.Format(dateLayout)
+	}
+	return employee
+}
+
+func parseDate(field, value string) (time.Time, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, apierror.BadInput("%s must be a date formatted YYYY-MM-DD", field)
+	}
+	return t, nil
+}
+
+func toID(field string, id int64) (int, error) {
+	if id <= 0 {
+		return 0, apierror.BadInput("%s must be a positive integer", field)
+	}
+	return int(id), nil
+}
+
+// GetEmployee returns one employee
+func (s *employeeService) GetEmployee(ctx context.Context, req *employeepb.GetEmployeeRequest) (*employeepb.Employee, error) {
+	id, err := toID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := s.controller.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employee), nil
+}
+
+// GetEmployeeByUser returns the employee of a user account
+func (s *employeeService) GetEmployeeByUser(ctx context.Context, req *employeepb.GetEmployeeByUserRequest) (*employeepb.Employee, error) {
+	userID, err := toID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := s.controller.GetEmployeeByContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employee), nil
+}
+
+// ListEmployees streams the matching employees page by page, so large results never sit in memory
+func (s *employeeService) ListEmployees(req *employeepb.ListEmployeesRequest, stream employeepb.EmployeeService_ListEmployeesServer) error {
+	var filter model.EmployeeFilter
+	if req.DepartmentId != nil {
+		departmentID := int(req.GetDepartmentId())
+		filter.DepartmentID = &departmentID
+	}
+	filter.Position = req.Position
+	filter.Search = req.Search
+
+	sortBy := req.GetSortBy()
+	if sortBy == "" {
+		sortBy = "id"
+	}
+	desc := req.GetDescending()
+
+	for number := pagination.PageDefaultNumber; ; number++ {
+		page := pagination.NewPage(number, listBatchSize, sortBy, desc)
+		employees, info, err := s.controller.ListEmployees(stream.Context(), filter, page)
+		if err != nil {
+			return err
+		}
+		for _, employee := range employees {
+			if err := stream.Send(toEmployee(*employee)); err != nil {
+				return err
+			}
+		}
+		if !info.HasNextPage {
+			return nil
+		}
+	}
+}
+
+// CreateEmployee adds an employee
+func (s *employeeService) CreateEmployee(ctx context.Context, req *employeepb.CreateEmployeeRequest) (*employeepb.Employee, error) {
+	userID, err := toID("user_id", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFirstName() == "" || req.GetLastName() == "" || req.GetEmail() == "" {
+		return nil, apierror.BadInput("first_name, last_name and email are required")
+	}
+	dob, err := parseDate("dob", req.GetDob())
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := s.controller.AddEmployee(ctx, model.Employee{
+		UserID:       userID,
+		FirstName:    req.GetFirstName(),
+		LastName:     req.GetLastName(),
+		Email:        req.GetEmail(),
+		Dob:          dob,
+		DepartmentID: int(req.GetDepartmentId()),
+		Position:     req.GetPosition(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employee), nil
+}
+
+// UpdateEmployee changes the fields set on req
+func (s *employeeService) UpdateEmployee(ctx context.Context, req *employeepb.UpdateEmployeeRequest) (*employeepb.Employee, error) {
+	id, err := toID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.controller.GetEmployeeByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// zero values are not written, so only the fields set change
+	changes := model.Employee{
+		FirstName:    req.GetFirstName(),
+		LastName:     req.GetLastName(),
+		DepartmentID: int(req.GetDepartmentId()),
+		Position:     req.GetPosition(),
+	}
+	if req.Dob != nil {
+		if changes.Dob, err = parseDate("dob", req.GetDob()); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.controller.UpdateEmployeeByID(ctx, id, changes); err != nil {
+		return nil, err
+	}
+	employee, err := s.controller.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEmployee(employee), nil
+}
+
+// DeleteEmployee removes an employee
+func (s *employeeService) DeleteEmployee(ctx context.Context, req *employeepb.DeleteEmployeeRequest) (*emptypb.Empty, error) {
+	id, err := toID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.controller.GetEmployeeByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.controller.DeleteEmployeeByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
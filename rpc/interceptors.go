@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"employee-management-system/pkg/apierror"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/middleware"
+)
+
+// publicServices may be called without credentials
+var publicServices = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
+
+func isPublic(fullMethod string) bool {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate returns ctx carrying the caller named by the authorization or x-api-key metadata
+func authenticate(ctx context.Context, auth Authenticator) (context.Context, error) {
+	header := http.Header{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			header[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+
+	ctx, err := auth.AuthenticateHeader(ctx, header)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, middleware.ErrUnauthorized.Error())
+	}
+	if _, err := middleware.UserFromContext(ctx); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return ctx, nil
+}
+
+func unaryAuth(auth Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(auth Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublic(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), auth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func unaryErrors(z zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, toStatus(ctx, z, info.FullMethod, err)
+	}
+}
+
+func streamErrors(z zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toStatus(ss.Context(), z, info.FullMethod, handler(srv, ss))
+	}
+}
+
+// toStatus converts err to the gRPC status matching its API error code. Internal failures are
+// logged and answered without their details.
+func toStatus(ctx context.Context, z zerolog.Logger, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	code := Code(apierror.Code(err))
+	if code == codes.Internal {
+		logging.Method(ctx, z, "toStatus").Err(err).Msgf("toStatus %s: %v", method, err)
+		return status.Error(code, "internal server error")
+	}
+	return status.Error(code, err.Error())
+}
+
+// Code returns the gRPC code of an API error code
+func Code(apiCode string) codes.Code {
+	switch apiCode {
+	case apierror.CodeBadUserInput:
+		return codes.InvalidArgument
+	case apierror.CodeUnauthenticated:
+		return codes.Unauthenticated
+	case apierror.CodeForbidden:
+		return codes.PermissionDenied
+	case apierror.CodeNotFound:
+		return codes.NotFound
+	case apierror.CodeConflict:
+		return codes.AlreadyExists
+	case apierror.CodeRateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// serverStream replaces the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context of the stream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
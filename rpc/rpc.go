@@ -0,0 +1,116 @@
+// Package rpc serves the employee controller over gRPC for backend services. It shares
+// controller.Operations, authentication and error codes with the GraphQL and REST APIs.
+package rpc
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=employee-management-system/rpc --go-grpc_out=. --go-grpc_opt=module=employee-management-system/rpc employee/v1/employee.proto
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/rpc/employeepb"
+)
+
+const packageName = "rpc"
+
+type (
+	// Authenticator resolves the caller of a call from its metadata given as HTTP headers,
+	// implemented by middleware.Middleware
+	Authenticator interface {
+		AuthenticateHeader(ctx context.Context, header http.Header) (context.Context, error)
+	}
+
+	// Server is the gRPC server
+	Server struct {
+		logger          zerolog.Logger
+		address         string
+		shutdownTimeout time.Duration
+		grpc            *grpc.Server
+		health          *health.Server
+	}
+)
+
+// New Server exposing the employee operations of c to callers authenticated by auth, together with
+// the standard health service and, when enabled, server reflection
+func New(z zerolog.Logger, cfg config.GRPC, shutdownTimeout time.Duration, c controller.Operations, auth Authenticator) *Server {
+	l := z.With().Str(helper.LogStrKeyModule, packageName).Logger()
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(l), unaryErrors(l), unaryAuth(auth)),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor(l), streamErrors(l), streamAuth(auth)),
+	)
+	employeepb.RegisterEmployeeServiceServer(s, &employeeService{controller: c})
+
+	h := health.NewServer()
+	h.SetServingStatus(employeepb.EmployeeService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, h)
+
+	if cfg.Reflection {
+		reflection.Register(s)
+	}
+
+	return &Server{
+		logger:          l,
+		address:         cfg.Address(),
+		shutdownTimeout: shutdownTimeout,
+		grpc:            s,
+		health:          h,
+	}
+}
+
+// Run listens on the configured port and serves until ctx is done
+func (s *Server) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.logger.Info().Msgf("Run: listening on %s", s.address)
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves on lis until ctx is done, then reports NOT_SERVING to health checks and waits up to
+// the shutdown timeout for in-flight calls and streams to finish
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.grpc.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info().Msgf("Serve: shutting down, draining for up to %s", s.shutdownTimeout)
+	s.health.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(s.shutdownTimeout):
+		s.logger.Warn().Msg("Serve: calls did not finish in time")
+		s.grpc.Stop()
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
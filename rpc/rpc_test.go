@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/rpc/employeepb"
+	"employee-management-system/storage"
+)
+
+// fakeOperations implements the operations the service calls, the embedded interface panics on anything else
+type fakeOperations struct {
+	controller.Operations
+	employees []model.Employee
+}
+
+func (f *fakeOperations) GetEmployeeByID(_ context.Context, id int) (model.Employee, error) {
+	for _, employee := range f.employees {
+		if employee.ID == id {
+			return employee, nil
+		}
+	}
+	return model.Employee{}, storage.ErrRecordNotFound
+}
+
+func (f *fakeOperations) ListEmployees(_ context.Context, _ model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error) {
+	number, size := *page.Number, *page.Size
+	var employees []*model.Employee
+	for i := (number - 1) * size; i < len(f.employees) && i < number*size; i++ {
+		employees = append(employees, &f.employees[i])
+	}
+	return employees, pagination.PageInfo{Page: number, Size: size, HasNextPage: number*size < len(f.employees)}, nil
+}
+
+func (f *fakeOperations) DeleteEmployeeByID(context.Context, int) error {
+	return errors.New("database is down")
+}
+
+// fakeAuth accepts the token "valid"
+type fakeAuth struct{}
+
+func (fakeAuth) AuthenticateHeader(ctx context.Context, header http.Header) (context.Context, error) {
+	switch header.Get("Authorization") {
+	case "":
+		return ctx, nil
+	case "Bearer valid":
+		return middleware.ContextWithUser(ctx, &model.User{ID: 1}), nil
+	default:
+		return nil, middleware.ErrInvalidToken
+	}
+}
+
+func dial(t *testing.T, ops controller.Operations) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := New(zerolog.Nop(), config.Default().GRPC, time.Second, ops, fakeAuth{})
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, lis) }()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return conn
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func Test_Server_Auth(t *testing.T) {
+	conn := dial(t, &fakeOperations{employees: []model.Employee{{ID: 1}}})
+	client := employeepb.NewEmployeeServiceClient(conn)
+
+	_, err := client.GetEmployee(context.Background(), &employeepb.GetEmployeeRequest{Id: 1})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.GetEmployee(withToken("forged"), &employeepb.GetEmployeeRequest{Id: 1})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.ListEmployees(context.Background(), &employeepb.ListEmployeesRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// health checks need no credentials
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: employeepb.EmployeeService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func Test_Server_Errors(t *testing.T) {
+	client := employeepb.NewEmployeeServiceClient(dial(t, &fakeOperations{employees: []model.Employee{{ID: 1}}}))
+
+	_, err := client.GetEmployee(withToken("valid"), &employeepb.GetEmployeeRequest{Id: 2})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetEmployee(withToken("valid"), &employeepb.GetEmployeeRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.DeleteEmployee(withToken("valid"), &employeepb.DeleteEmployeeRequest{Id: 1})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.NotContains(t, err.Error(), "database is down")
+
+	employee, err := client.GetEmployee(withToken("valid"), &employeepb.GetEmployeeRequest{Id: 1})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, employee.Id)
+}
+
+func Test_Server_ListEmployees(t *testing.T) {
+	ops := &fakeOperations{}
+	for i := 1; i <= listBatchSize+5; i++ {
+		ops.employees = append(ops.employees, model.Employee{ID: i})
+	}
+	client := employeepb.NewEmployeeServiceClient(dial(t, ops))
+
+	stream, err := client.ListEmployees(withToken("valid"), &employeepb.ListEmployeesRequest{})
+	require.NoError(t, err)
+	var ids []int64
+	for {
+		employee, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, employee.Id)
+	}
+	require.Len(t, ids, listBatchSize+5)
+	require.EqualValues(t, listBatchSize+5, ids[len(ids)-1])
+}
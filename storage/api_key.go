@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// APIKeyDatabase enlist all possible storage operations for service account API keys
+//
+//go:generate mockgen -source api_key.go -destination ./mock/mock_api_key.go -package mock APIKeyDatabase
+type APIKeyDatabase interface {
+	AddAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error)
+	GetAPIKeyByID(ctx context.Context, id int) (model.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (model.APIKey, error)
+	GetAllAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	RevokeAPIKeyByID(ctx context.Context, id int, at time.Time) (model.APIKey, error)
+	UpdateAPIKeyLastUsed(ctx context.Context, id int, at time.Time) error
+}
+
+// APIKey object
+type APIKey struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewAPIKey creates a new reference to the APIKey storage entity
+func NewAPIKey(s *Storage) *APIKeyDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "api_key").Logger()
+	apiKey := &APIKey{
+		logger:  l,
+		storage: s,
+	}
+	apiKeyDatabase := APIKeyDatabase(apiKey)
+	return &apiKeyDatabase
+}
+
+// AddAPIKey adds a new row into the api_keys table referencing the service account by user_id column
+func (a *APIKey) AddAPIKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	db := a.storage.DB.WithContext(ctx).Omit("User").Create(&key)
+	if db.Error != nil {
+		logging.Method(ctx, a.logger, "AddAPIKey").Err(db.Error).Msgf("APIKey::AddAPIKey error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return model.APIKey{}, ErrRecordCreatingFailed
+	}
+	return key, nil
+}
+
+// GetAPIKeyByID retrieves a single row together with its service account
+func (a *APIKey) GetAPIKeyByID(ctx context.Context, id int) (model.APIKey, error) {
+	var key model.APIKey
+	db := a.storage.DB.WithContext(ctx).Preload("User").Where("id = ?", id).Find(&key)
+	if db.Error != nil || key.ID == 0 {
+		logging.Method(ctx, a.logger, "GetAPIKeyByID").Err(db.Error).Msgf("APIKey::GetAPIKeyByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return key, ErrRecordNotFound
+	}
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves the key matching the supplied hash together with its service account
+func (a *APIKey) GetAPIKeyByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	var key model.APIKey
+	db := a.storage.DB.WithContext(ctx).Preload("User").Where("key_hash = ?", hash).Find(&key)
+	if db.Error != nil || key.ID == 0 {
+		logging.Method(ctx, a.logger, "GetAPIKeyByHash").Err(db.Error).Msgf("APIKey::GetAPIKeyByHash error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return key, ErrRecordNotFound
+	}
+	return key, nil
+}
+
+// GetAllAPIKeys retrieves all keys, newest first
+func (a *APIKey) GetAllAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	db := a.storage.DB.WithContext(ctx).Preload("User").Order("created_at DESC").Find(&keys)
+	if db.Error != nil {
+		logging.Method(ctx, a.logger, "GetAllAPIKeys").Err(db.Error).Msgf("APIKey::GetAllAPIKeys error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return keys, nil
+}
+
+// RevokeAPIKeyByID marks the key as revoked so it is no longer accepted
+func (a *APIKey) RevokeAPIKeyByID(ctx context.Context, id int, at time.Time) (model.APIKey, error) {
+	db := a.storage.DB.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if db.Error != nil {
+		logging.Method(ctx, a.logger, "RevokeAPIKeyByID").Err(db.Error).Msgf("APIKey::RevokeAPIKeyByID error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return model.APIKey{}, ErrRecordUpdateFailed
+	}
+	return a.GetAPIKeyByID(ctx, id)
+}
+
+// UpdateAPIKeyLastUsed records when the key was last presented
+func (a *APIKey) UpdateAPIKeyLastUsed(ctx context.Context, id int, at time.Time) error {
+	db := a.storage.DB.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", id).Update("last_used_at", at)
+	if db.Error != nil {
+		logging.Method(ctx, a.logger, "UpdateAPIKeyLastUsed").Err(db.Error).Msgf("APIKey::UpdateAPIKeyLastUsed error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	return nil
+}
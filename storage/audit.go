@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// AuditDatabase enlist all possible storage operations for the audit trail, entries are only added
+//
+//go:generate mockgen -source audit.go -destination ./mock/mock_audit.go -package mock AuditDatabase
+type AuditDatabase interface {
+	AddAuditEntry(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error)
+	GetAuditEntriesBySubject(ctx context.Context, userID, employeeID int) ([]*model.AuditEntry, error)
+}
+
+// Audit object
+type Audit struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewAudit creates a new reference to the AuditEntry storage entity
+func NewAudit(s *Storage) *AuditDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "audit").Logger()
+	audit := &Audit{
+		logger:  l,
+		storage: s,
+	}
+	auditDatabase := AuditDatabase(audit)
+	return &auditDatabase
+}
+
+// AddAuditEntry adds a new row into the audit_entries table
+func (a *Audit) AddAuditEntry(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	db := a.storage.DB.WithContext(ctx).Create(&entry)
+	if db.Error != nil {
+		logging.Method(ctx, a.logger, "AddAuditEntry").Err(db.Error).Msgf("Audit::AddAuditEntry error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return model.AuditEntry{}, ErrRecordCreatingFailed
+	}
+	return entry, nil
+}
+
+// GetAuditEntriesBySubject retrieves the entries about the user or the employee, oldest first. A
+// zero id matches nothing, it is stored as NULL.
+func (a *Audit) GetAuditEntriesBySubject(ctx context.Context, userID, employeeID int) ([]*model.AuditEntry, error) {
+	var entries []*model.AuditEntry
+	db := a.storage.DB.WithContext(ctx).
+		Where("(subject_user_id = ? OR subject_employee_id = ?)", userID, employeeID).
+		Order("created_at, id").
+		Find(&entries)
+	if db.Error != nil {
+		logging.Method(ctx, a.logger, "GetAuditEntriesBySubject").Err(db.Error).Msgf("Audit::GetAuditEntriesBySubject error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// DepartmentDatabase enlist all possible storage operations for Departments
+//
+//go:generate mockgen -source department.go -destination ./mock/mock_department.go -package mock DepartmentDatabase
+type DepartmentDatabase interface {
+	AddDepartment(ctx context.Context, department model.Department) (model.Department, error)
+	AddDepartments(ctx context.Context, departments []*model.Department) error
+	GetDepartmentByID(ctx context.Context, id int) (model.Department, error)
+	GetAllDepartments(ctx context.Context) ([]*model.Department, error)
+	UpdateDepartmentByID(ctx context.Context, id int, department model.Department) (model.Department, error)
+	DeleteDepartmentByID(ctx context.Context, id int) error
+}
+
+// Department object
+type Department struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewDepartment creates a new reference to the Department storage entity
+func NewDepartment(s *Storage) *DepartmentDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "department").Logger()
+	department := &Department{
+		logger:  l,
+		storage: s,
+	}
+	departmentDatabase := DepartmentDatabase(department)
+	return &departmentDatabase
+}
+
+// AddDepartment adds a new row into the departments table
+func (d *Department) AddDepartment(ctx context.Context, department model.Department) (model.Department, error) {
+	db := d.storage.DB.WithContext(ctx).Create(&department)
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "AddDepartment").Err(db.Error).Msgf("Department::AddDepartment error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return model.Department{}, ErrRecordCreatingFailed
+	}
+	return department, nil
+}
+
+// AddDepartments adds the departments in batches, setting their IDs
+func (d *Department) AddDepartments(ctx context.Context, departments []*model.Department) error {
+	db := d.storage.DB.WithContext(ctx).CreateInBatches(departments, insertBatchSize)
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "AddDepartments").Err(db.Error).Msgf("Department::AddDepartments error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return ErrRecordCreatingFailed
+	}
+	return nil
+}
+
+// GetDepartmentByID retrieves a single row
+func (d *Department) GetDepartmentByID(ctx context.Context, id int) (model.Department, error) {
+	var department model.Department
+	db := d.storage.DB.WithContext(ctx).Where("id = ?", id).Find(&department)
+	if db.Error != nil || department.ID == 0 {
+		logging.Method(ctx, d.logger, "GetDepartmentByID").Err(db.Error).Msgf("Department::GetDepartmentByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return department, ErrRecordNotFound
+	}
+	return department, nil
+}
+
+// GetAllDepartments retrieves all departments
+func (d *Department) GetAllDepartments(ctx context.Context) ([]*model.Department, error) {
+	var departments []*model.Department
+	db := d.storage.DB.WithContext(ctx).Order("department_name").Find(&departments)
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "GetAllDepartments").Err(db.Error).Msgf("Department::GetAllDepartments error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return departments, nil
+}
+
+// UpdateDepartmentByID sets supported new values for a row accordingly
+func (d *Department) UpdateDepartmentByID(ctx context.Context, id int, department model.Department) (model.Department, error) {
+	db := d.storage.DB.WithContext(ctx).Model(&model.Department{ID: id}).UpdateColumns(model.Department{
+		DepartmentName: department.DepartmentName,
+	})
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "UpdateDepartmentByID").Err(db.Error).Msgf("Department::UpdateDepartmentByID error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return department, ErrRecordUpdateFailed
+	}
+	if db.RowsAffected == 0 {
+		return department, ErrRecordNotFound
+	}
+	return d.GetDepartmentByID(ctx, id)
+}
+
+// DeleteDepartmentByID removes record completely from the storage
+func (d *Department) DeleteDepartmentByID(ctx context.Context, id int) error {
+	db := d.storage.DB.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&model.Department{})
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "DeleteDepartmentByID").Err(db.Error).Msgf("Department::DeleteDepartmentByID error: %v, (%v)", ErrDeleteFailed, db.Error)
+		return ErrDeleteFailed
+	}
+	if db.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// DocumentDatabase enlist all possible storage operations for the metadata of employee documents,
+// their content is kept in the blob store
+//
+//go:generate mockgen -source document.go -destination ./mock/mock_document.go -package mock DocumentDatabase
+type DocumentDatabase interface {
+	AddDocument(ctx context.Context, document model.Document) (model.Document, error)
+	GetDocumentByID(ctx context.Context, id int) (model.Document, error)
+	GetDocumentsByEmployeeID(ctx context.Context, employeeID int) ([]*model.Document, error)
+	DeleteDocumentByID(ctx context.Context, id int) error
+}
+
+// Document object
+type Document struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewDocument creates a new reference to the Document storage entity
+func NewDocument(s *Storage) *DocumentDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "document").Logger()
+	document := &Document{
+		logger:  l,
+		storage: s,
+	}
+	documentDatabase := DocumentDatabase(document)
+	return &documentDatabase
+}
+
+// AddDocument adds a new row into the documents table referencing employees by employee_id column
+func (d *Document) AddDocument(ctx context.Context, document model.Document) (model.Document, error) {
+	db := d.storage.DB.WithContext(ctx).Create(&document)
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "AddDocument").Err(db.Error).Msgf("Document::AddDocument error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return model.Document{}, ErrRecordCreatingFailed
+	}
+	return document, nil
+}
+
+// GetDocumentByID retrieves a single row
+func (d *Document) GetDocumentByID(ctx context.Context, id int) (model.Document, error) {
+	var document model.Document
+	db := d.storage.DB.WithContext(ctx).Where("id = ?", id).Find(&document)
+	if db.Error != nil || document.ID == 0 {
+		logging.Method(ctx, d.logger, "GetDocumentByID").Err(db.Error).Msgf("Document::GetDocumentByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return document, ErrRecordNotFound
+	}
+	return document, nil
+}
+
+// GetDocumentsByEmployeeID retrieves the documents of an employee, most recent first
+func (d *Document) GetDocumentsByEmployeeID(ctx context.Context, employeeID int) ([]*model.Document, error) {
+	var documents []*model.Document
+	db := d.storage.DB.WithContext(ctx).Where("employee_id = ?", employeeID).Order("created_at DESC, id DESC").Find(&documents)
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "GetDocumentsByEmployeeID").Err(db.Error).Msgf("Document::GetDocumentsByEmployeeID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return documents, nil
+}
+
+// DeleteDocumentByID removes the row, the caller removes the content from the blob store
+func (d *Document) DeleteDocumentByID(ctx context.Context, id int) error {
+	db := d.storage.DB.WithContext(ctx).Where("id = ?", id).Delete(&model.Document{})
+	if db.Error != nil {
+		logging.Method(ctx, d.logger, "DeleteDocumentByID").Err(db.Error).Msgf("Document::DeleteDocumentByID error: %v, (%v)", ErrDeleteFailed, db.Error)
+		return ErrDeleteFailed
+	}
+	return nil
+}
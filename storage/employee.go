@@ -4,9 +4,15 @@ import (
 	"context"
 
 	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"employee-management-system/model"
+	"employee-management-system/model/pagination"
 	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/pii"
+	"employee-management-system/pkg/tenant"
 )
 
 // EmployeeDatabase enlist all possible storage operations for Employee entity for User
@@ -14,11 +20,15 @@ import (
 //go:generate mockgen -source employee.go -destination ./mock/mock_employee.go -package mock EmployeeDatabase
 type EmployeeDatabase interface {
 	AddEmployee(ctx context.Context, employee model.Employee) (model.Employee, error)
+	AddEmployees(ctx context.Context, employees []*model.Employee) error
 	GetEmployeeByID(ctx context.Context, ID int) (model.Employee, error)
 	GetEmployeeByContext(ctx context.Context, userID int) (model.Employee, error)
 	GetAllEmployees(ctx context.Context) ([]*model.Employee, error)
+	ListEmployees(ctx context.Context, filter model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error)
 	UpdateEmployeeByID(ctx context.Context, id int, employee model.Employee) (model.Employee, error)
+	UpdateEmployeePhoto(ctx context.Context, id int, photoKey string) error
 	DeleteEmployeeByID(ctx context.Context, id int) error
+	ReencryptEmployees(ctx context.Context, batch int) (int, error)
 }
 
 // Employee object
@@ -40,20 +50,34 @@ func NewEmployee(s *Storage) *EmployeeDatabase {
 
 // AddEmployee adds a new row into the employee table referencing users by user_id column
 func (e *Employee) AddEmployee(ctx context.Context, employee model.Employee) (model.Employee, error) {
+	employee.EmailIndex = pii.Current().BlindIndex(employee.Email)
 	db := e.storage.DB.WithContext(ctx).Create(&employee)
 	if db.Error != nil {
-		e.logger.Err(db.Error).Msgf("Employee::AddEmployee error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		logging.Method(ctx, e.logger, "AddEmployee").Err(db.Error).Msgf("Employee::AddEmployee error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
 		return model.Employee{}, ErrRecordCreatingFailed
 	}
 	return employee, nil
 }
 
+// AddEmployees adds the employees in batches, setting their IDs
+func (e *Employee) AddEmployees(ctx context.Context, employees []*model.Employee) error {
+	for _, employee := range employees {
+		employee.EmailIndex = pii.Current().BlindIndex(employee.Email)
+	}
+	db := e.storage.DB.WithContext(ctx).CreateInBatches(employees, insertBatchSize)
+	if db.Error != nil {
+		logging.Method(ctx, e.logger, "AddEmployees").Err(db.Error).Msgf("Employee::AddEmployees error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return ErrRecordCreatingFailed
+	}
+	return nil
+}
+
 // GetEmployeeByID retrieves a single row
 func (e *Employee) GetEmployeeByID(ctx context.Context, ID int) (model.Employee, error) {
 	var employee model.Employee
 	db := e.storage.DB.WithContext(ctx).Where("id = ?", ID).Find(&employee)
 	if db.Error != nil || employee.ID == 0 {
-		e.logger.Err(db.Error).Msgf("Employee::GetEmployeeByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		logging.Method(ctx, e.logger, "GetEmployeeByID").Err(db.Error).Msgf("Employee::GetEmployeeByID error: %v, (%v)", ErrRecordNotFound, db.Error)
 		return employee, ErrRecordNotFound
 	}
 
@@ -65,7 +89,7 @@ func (e *Employee) GetEmployeeByContext(ctx context.Context, userID int) (model.
 	var employee model.Employee
 	db := e.storage.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&employee)
 	if db.Error != nil || employee.ID == 0 {
-		e.logger.Err(db.Error).Msgf("Employee::GetEmployeeByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		logging.Method(ctx, e.logger, "GetEmployeeByContext").Err(db.Error).Msgf("Employee::GetEmployeeByContext error: %v, (%v)", ErrRecordNotFound, db.Error)
 		return employee, ErrRecordNotFound
 	}
 
@@ -77,13 +101,71 @@ func (e *Employee) GetAllEmployees(ctx context.Context) ([]*model.Employee, erro
 	var employees []*model.Employee
 	db := e.storage.DB.WithContext(ctx).Find(&employees)
 	if db.Error != nil {
-		e.logger.Err(db.Error).Msgf("Employee::GetAllEmployees error: %v, (%v)", ErrRecordNotFound, db.Error)
+		logging.Method(ctx, e.logger, "GetAllEmployees").Err(db.Error).Msgf("Employee::GetAllEmployees error: %v, (%v)", ErrRecordNotFound, db.Error)
 		return nil, ErrRecordNotFound
 	}
 
 	return employees, nil
 }
 
+// employeeSortColumns maps the sort keys accepted from callers to columns, encrypted columns do not sort
+var employeeSortColumns = map[string]string{
+	"id":        "id",
+	"firstName": "first_name",
+	"lastName":  "last_name",
+	"position":  "position",
+}
+
+// ListEmployees retrieves a page of employees matching filter
+func (e *Employee) ListEmployees(ctx context.Context, filter model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error) {
+	page = getPaging(page)
+	query := e.storage.DB.WithContext(ctx).Model(&model.Employee{})
+	if filter.DepartmentID != nil {
+		query = query.Where("departmentID = ?", *filter.DepartmentID)
+	}
+	if filter.Position != nil {
+		query = query.Where("position = ?", *filter.Position)
+	}
+	if filter.Search != nil && *filter.Search != "" {
+		like := "%" + *filter.Search + "%"
+		if filter.SearchEmail {
+			// the email is encrypted, only the whole address matches through its blind index
+			query = query.Where("first_name LIKE ? OR last_name LIKE ? OR email_index = ?", like, like, pii.Current().BlindIndex(*filter.Search))
+		} else {
+			query = query.Where("first_name LIKE ? OR last_name LIKE ?", like, like)
+		}
+	}
+
+	var total int64
+	if db := query.Count(&total); db.Error != nil {
+		logging.Method(ctx, e.logger, "ListEmployees").Err(db.Error).Msgf("Employee::ListEmployees error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, pagination.PageInfo{}, ErrRecordNotFound
+	}
+
+	column, ok := employeeSortColumns[*page.SortBy]
+	if !ok {
+		column = "id"
+	}
+	var employees []*model.Employee
+	db := query.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: *page.SortDirectionDesc}).
+		Offset((*page.Number - 1) * *page.Size).
+		Limit(*page.Size).
+		Find(&employees)
+	if db.Error != nil {
+		logging.Method(ctx, e.logger, "ListEmployees").Err(db.Error).Msgf("Employee::ListEmployees error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, pagination.PageInfo{}, ErrRecordNotFound
+	}
+
+	return employees, pagination.PageInfo{
+		Page:            *page.Number,
+		Size:            *page.Size,
+		HasNextPage:     int64(*page.Number**page.Size) < total,
+		HasPreviousPage: *page.Number > 1,
+		TotalCount:      total,
+	}, nil
+}
+
 // UpdateEmployeeByID sets supported new values for a row accordingly
 func (e *Employee) UpdateEmployeeByID(ctx context.Context, id int, employee model.Employee) (model.Employee, error) {
 	db := e.storage.DB.WithContext(ctx).Model(&model.Employee{
@@ -96,18 +178,74 @@ func (e *Employee) UpdateEmployeeByID(ctx context.Context, id int, employee mode
 		Position:     employee.Position,
 	})
 	if db.Error != nil {
-		e.logger.Err(db.Error).Msgf("Employee::UpdateByID error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		logging.Method(ctx, e.logger, "UpdateEmployeeByID").Err(db.Error).Msgf("Employee::UpdateEmployeeByID error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
 		return employee, ErrRecordUpdateFailed
 	}
 	return employee, nil
 }
 
+// UpdateEmployeePhoto points the employee at the profile photo stored under photoKey, or at none
+// when empty
+func (e *Employee) UpdateEmployeePhoto(ctx context.Context, id int, photoKey string) error {
+	var value interface{}
+	if photoKey != "" {
+		value = photoKey
+	}
+	db := e.storage.DB.WithContext(ctx).Model(&model.Employee{}).Where("id = ?", id).Update("photo_key", value)
+	if db.Error != nil {
+		logging.Method(ctx, e.logger, "UpdateEmployeePhoto").Err(db.Error).Msgf("Employee::UpdateEmployeePhoto error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	if db.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
 // DeleteEmployeeByID removes record completely from the storage
 func (e *Employee) DeleteEmployeeByID(ctx context.Context, id int) error {
 	db := e.storage.DB.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&model.Employee{})
 	if db.Error != nil {
-		e.logger.Err(db.Error).Msgf("Employee::HardDeleteByID error: %v, (%v)", ErrDeleteFailed, db.Error)
+		logging.Method(ctx, e.logger, "DeleteEmployeeByID").Err(db.Error).Msgf("Employee::DeleteEmployeeByID error: %v, (%v)", ErrDeleteFailed, db.Error)
 		return ErrDeleteFailed
 	}
 	return nil
 }
+
+// ReencryptEmployees writes the personal data of every employee of every tenant again, batch rows
+// at a time, so it is encrypted under the primary key of the keyring and its blind index is set. It
+// returns the number of employees written.
+func (e *Employee) ReencryptEmployees(ctx context.Context, batch int) (int, error) {
+	ctx = tenant.Unscoped(ctx)
+	var (
+		count  int
+		lastID int
+	)
+	for {
+		var employees []*model.Employee
+		db := e.storage.DB.WithContext(ctx).Where("id > ?", lastID).Order("id").Limit(batch).Find(&employees)
+		if db.Error != nil {
+			logging.Method(ctx, e.logger, "ReencryptEmployees").Err(db.Error).Msgf("Employee::ReencryptEmployees error: %v, (%v)", ErrRecordNotFound, db.Error)
+			return count, ErrRecordNotFound
+		}
+		if len(employees) == 0 {
+			return count, nil
+		}
+
+		err := e.storage.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, employee := range employees {
+				employee.EmailIndex = pii.Current().BlindIndex(employee.Email)
+				if err := tx.Model(employee).Select("email", "email_index", "dob").Updates(employee).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logging.Method(ctx, e.logger, "ReencryptEmployees").Err(err).Msgf("Employee::ReencryptEmployees error: %v, (%v)", ErrRecordUpdateFailed, err)
+			return count, ErrRecordUpdateFailed
+		}
+		count += len(employees)
+		lastID = employees[len(employees)-1].ID
+	}
+}
@@ -21,4 +21,8 @@ var (
 	ErrDuplicateRecord = errors.New("record already exist, duplicate record")
 	//ErrUnauthorizedAccess if error occurred while comparing the designated role sent to the role required to perform a certain action
 	ErrUnauthorizedAccess = errors.New("you have no access to perform this task")
+	// ErrTenantRequired when a query on tenant data is made without a tenant in its context
+	ErrTenantRequired = errors.New("no tenant to scope the query to")
+	// ErrCrossTenant when a record is written for another tenant than the one of the context
+	ErrCrossTenant = errors.New("record belongs to another tenant")
 )
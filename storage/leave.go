@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// LeaveDatabase enlist all possible storage operations for LeaveRecords
+//
+//go:generate mockgen -source leave.go -destination ./mock/mock_leave.go -package mock LeaveDatabase
+type LeaveDatabase interface {
+	AddLeaveRecords(ctx context.Context, records []*model.LeaveRecord) error
+	GetLeaveRecordsByEmployeeID(ctx context.Context, employeeID int) ([]*model.LeaveRecord, error)
+}
+
+// Leave object
+type Leave struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewLeave creates a new reference to the LeaveRecord storage entity
+func NewLeave(s *Storage) *LeaveDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "leave").Logger()
+	leave := &Leave{
+		logger:  l,
+		storage: s,
+	}
+	leaveDatabase := LeaveDatabase(leave)
+	return &leaveDatabase
+}
+
+// AddLeaveRecords adds the records in batches, setting their IDs
+func (l *Leave) AddLeaveRecords(ctx context.Context, records []*model.LeaveRecord) error {
+	db := l.storage.DB.WithContext(ctx).CreateInBatches(records, insertBatchSize)
+	if db.Error != nil {
+		logging.Method(ctx, l.logger, "AddLeaveRecords").Err(db.Error).Msgf("Leave::AddLeaveRecords error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return ErrRecordCreatingFailed
+	}
+	return nil
+}
+
+// GetLeaveRecordsByEmployeeID retrieves the leave of an employee, earliest first
+func (l *Leave) GetLeaveRecordsByEmployeeID(ctx context.Context, employeeID int) ([]*model.LeaveRecord, error) {
+	var records []*model.LeaveRecord
+	db := l.storage.DB.WithContext(ctx).Where("employee_id = ?", employeeID).Order("starts_on").Find(&records)
+	if db.Error != nil {
+		logging.Method(ctx, l.logger, "GetLeaveRecordsByEmployeeID").Err(db.Error).Msgf("Leave::GetLeaveRecordsByEmployeeID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return records, nil
+}
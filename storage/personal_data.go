@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+	"employee-management-system/pkg/pii"
+)
+
+// erasedName replaces the names of an erased employee
+const erasedName = "Erased"
+
+// PersonalDataDatabase enlist the storage operations of data subject requests, they span the
+// employee, its user and everything referencing them
+//
+//go:generate mockgen -source personal_data.go -destination ./mock/mock_personal_data.go -package mock PersonalDataDatabase
+type PersonalDataDatabase interface {
+	GetPersonalData(ctx context.Context, employeeID int, at time.Time) (model.PersonalData, error)
+	ErasePersonalData(ctx context.Context, employeeID int, entry model.AuditEntry, at time.Time) (model.AuditEntry, error)
+}
+
+// PersonalData object
+type PersonalData struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewPersonalData creates a new reference to the PersonalData storage entity
+func NewPersonalData(s *Storage) *PersonalDataDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "personal_data").Logger()
+	personalData := &PersonalData{
+		logger:  l,
+		storage: s,
+	}
+	personalDataDatabase := PersonalDataDatabase(personalData)
+	return &personalDataDatabase
+}
+
+// GetPersonalData assembles everything held about the employee and its user: the profile, sign
+// ins, API keys, leave, documents and the audit entries about them
+func (p *PersonalData) GetPersonalData(ctx context.Context, employeeID int, at time.Time) (model.PersonalData, error) {
+	var (
+		employee  model.Employee
+		user      model.User
+		sessions  []*model.Session
+		apiKeys   []*model.APIKey
+		leave     []*model.LeaveRecord
+		documents []*model.Document
+		entries   []*model.AuditEntry
+	)
+	db := p.storage.DB.WithContext(ctx)
+	if err := db.Where("id = ?", employeeID).Find(&employee).Error; err != nil || employee.ID == 0 {
+		logging.Method(ctx, p.logger, "GetPersonalData").Err(err).Msgf("PersonalData::GetPersonalData error: %v, (%v)", ErrRecordNotFound, err)
+		return model.PersonalData{}, ErrRecordNotFound
+	}
+	err := db.Where("employee_id = ?", employeeID).Order("starts_on").Find(&leave).Error
+	if err == nil {
+		err = db.Where("employee_id = ?", employeeID).Order("created_at, id").Find(&documents).Error
+	}
+	if err == nil && employee.UserID != 0 {
+		err = db.Where("id = ?", employee.UserID).Find(&user).Error
+	}
+	if err == nil && user.ID != 0 {
+		err = db.Where("user_id = ?", user.ID).Order("created_at").Find(&sessions).Error
+	}
+	if err == nil && user.ID != 0 {
+		err = db.Where("user_id = ?", user.ID).Order("created_at").Find(&apiKeys).Error
+	}
+	if err == nil {
+		err = db.Where("(subject_user_id = ? OR subject_employee_id = ?)", user.ID, employee.ID).Order("created_at, id").Find(&entries).Error
+	}
+	if err != nil {
+		logging.Method(ctx, p.logger, "GetPersonalData").Err(err).Msgf("PersonalData::GetPersonalData error: %v, (%v)", ErrRecordNotFound, err)
+		return model.PersonalData{}, ErrRecordNotFound
+	}
+
+	data := model.PersonalData{
+		ExportedAt:   at,
+		Employee:     personalEmployee(employee),
+		Sessions:     make([]model.PersonalSession, 0, len(sessions)),
+		APIKeys:      make([]model.PersonalAPIKey, 0, len(apiKeys)),
+		LeaveRecords: make([]model.PersonalLeaveRecord, 0, len(leave)),
+		Documents:    make([]model.PersonalDocument, 0, len(documents)),
+		AuditEntries: make([]model.PersonalAuditEntry, 0, len(entries)),
+	}
+	if user.ID != 0 {
+		data.User = personalUser(user)
+	}
+	for _, s := range sessions {
+		data.Sessions = append(data.Sessions, model.PersonalSession{UserAgent: s.UserAgent, IPAddress: s.IPAddress, CreatedAt: s.CreatedAt, LastSeenAt: s.LastSeenAt, RevokedAt: s.RevokedAt})
+	}
+	for _, k := range apiKeys {
+		data.APIKeys = append(data.APIKeys, model.PersonalAPIKey{Prefix: k.Prefix, CreatedAt: k.CreatedAt, ExpiresAt: k.ExpiresAt, LastUsedAt: k.LastUsedAt, RevokedAt: k.RevokedAt})
+	}
+	for _, l := range leave {
+		data.LeaveRecords = append(data.LeaveRecords, model.PersonalLeaveRecord{Kind: l.Kind, Status: l.Status, StartsOn: l.StartsOn, EndsOn: l.EndsOn})
+	}
+	for _, d := range documents {
+		data.Documents = append(data.Documents, model.PersonalDocument{ID: d.ID, Kind: d.Kind, FileName: d.FileName, ContentType: d.ContentType, Size: d.Size, Checksum: d.Checksum, CreatedAt: d.CreatedAt})
+	}
+	for _, e := range entries {
+		data.AuditEntries = append(data.AuditEntries, model.PersonalAuditEntry{Action: e.Action, ActorID: e.ActorID, Detail: e.Detail, CreatedAt: e.CreatedAt})
+	}
+	return data, nil
+}
+
+// ErasePersonalData anonymizes the employee and its user at the time at and records entry about it,
+// all in one transaction. Names, email and birth date are overwritten and the photo unset; the user
+// can no longer sign in, its sessions and API keys are revoked and its second factor removed. The
+// employee row, its department, position, reporting line and leave, and the audit trail are kept
+// for reporting and legal retention. It returns the recorded entry.
+func (p *PersonalData) ErasePersonalData(ctx context.Context, employeeID int, entry model.AuditEntry, at time.Time) (model.AuditEntry, error) {
+	err := p.storage.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var employee model.Employee
+		if err := tx.Where("id = ?", employeeID).Find(&employee).Error; err != nil {
+			return err
+		}
+		if employee.ID == 0 {
+			return ErrRecordNotFound
+		}
+
+		erased := model.Employee{
+			FirstName: erasedName,
+			LastName:  erasedName,
+			Email:     fmt.Sprintf("erased-%d@invalid", employee.ID),
+			ErasedAt:  &at,
+		}
+		erased.EmailIndex = pii.Current().BlindIndex(erased.Email)
+		// selected so the zero birth date is written, as NULL, and the photo key emptied
+		if err := tx.Model(&employee).Select("first_name", "last_name", "email", "email_index", "dob", "photo_key", "erased_at").Updates(&erased).Error; err != nil {
+			return err
+		}
+
+		if employee.UserID != 0 {
+			db := tx.Model(&model.User{}).Where("id = ?", employee.UserID).Updates(map[string]interface{}{
+				"user_name":       fmt.Sprintf("erased-%d", employee.UserID),
+				"password":        "",
+				"totp_secret":     nil,
+				"totp_enabled_at": nil,
+				"locked_at":       at,
+			})
+			if db.Error != nil {
+				return db.Error
+			}
+			if db.RowsAffected == 0 {
+				// the user belongs to another tenant, its sign ins are not for this one to revoke
+				employee.UserID = 0
+			}
+		}
+		if employee.UserID != 0 {
+			if err := tx.Where("user_id = ?", employee.UserID).Delete(&model.RecoveryCode{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", employee.UserID).Update("revoked_at", at).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.APIKey{}).Where("user_id = ? AND revoked_at IS NULL", employee.UserID).Update("revoked_at", at).Error; err != nil {
+				return err
+			}
+		}
+
+		entry.Action = model.AuditPersonalDataErased
+		entry.SubjectEmployeeID, entry.SubjectUserID = employee.ID, employee.UserID
+		entry.CreatedAt = at
+		return tx.Create(&entry).Error
+	})
+	if errors.Is(err, ErrRecordNotFound) {
+		return model.AuditEntry{}, err
+	}
+	if err != nil {
+		logging.Method(ctx, p.logger, "ErasePersonalData").Err(err).Msgf("PersonalData::ErasePersonalData error: %v, (%v)", ErrRecordUpdateFailed, err)
+		return model.AuditEntry{}, ErrRecordUpdateFailed
+	}
+	return entry, nil
+}
+
+func personalEmployee(e model.Employee) model.PersonalEmployee {
+	employee := model.PersonalEmployee{
+		ID:           e.ID,
+		FirstName:    e.FirstName,
+		LastName:     e.LastName,
+		Email:        e.Email,
+		DepartmentID: e.DepartmentID,
+		Position:     e.Position,
+		ManagerID:    e.ManagerID,
+		UpdatedAt:    e.UpdatedAt,
+		HasPhoto:     e.PhotoKey != "",
+		ErasedAt:     e.ErasedAt,
+	}
+	if !e.Dob.IsZero() {
+		employee.Dob = &e.Dob
+	}
+	return employee
+}
+
+func personalUser(u model.User) *model.PersonalUser {
+	user := &model.PersonalUser{
+		ID:               u.ID,
+		Role:             u.Kind.String(),
+		TwoFactorEnabled: u.TwoFactorEnabled(),
+		LockedAt:         u.LockedAt,
+		CreatedAt:        u.CreatedAt,
+		UpdatedAt:        u.UpdatedAt,
+	}
+	if u.UserName != nil {
+		user.UserName = *u.UserName
+	}
+	return user
+}
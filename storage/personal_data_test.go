@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/tenant"
+)
+
+func Test_PersonalData(t *testing.T) {
+	s := newSQLiteStorage(t)
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	personalData := *NewPersonalData(s)
+	audit := *NewAudit(s)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	userName := "jane"
+	secret := "JBSWY3DPEHPK3PXP"
+	user, err := (*NewUser(s)).Register(ctx, model.User{UserName: &userName, Password: "hash", Kind: model.KindStaff, TOTPSecret: &secret, TOTPEnabledAt: &now})
+	require.NoError(t, err)
+	employee, err := (*NewEmployee(s)).AddEmployee(ctx, model.Employee{UserID: user.ID, FirstName: "Jane", LastName: "Doe", Email: "jane@company.com", Dob: time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC), Position: "engineer"})
+	require.NoError(t, err)
+	_, err = (*NewSession(s)).AddSession(ctx, model.Session{UserID: user.ID, UserAgent: "curl", IPAddress: "10.0.0.1", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = (*NewAPIKey(s)).AddAPIKey(ctx, model.APIKey{UserID: user.ID, Prefix: "ems_0123abcd", KeyHash: "hash"})
+	require.NoError(t, err)
+	require.NoError(t, (*NewLeave(s)).AddLeaveRecords(ctx, []*model.LeaveRecord{{EmployeeID: employee.ID, Kind: model.LeaveKindAnnual, Status: model.LeaveStatusApproved, StartsOn: now, EndsOn: now}}))
+	require.NoError(t, (*NewEmployee(s)).UpdateEmployeePhoto(ctx, employee.ID, "1/1/photos/v1"))
+	_, err = (*NewDocument(s)).AddDocument(ctx, model.Document{EmployeeID: employee.ID, Kind: model.DocumentKindContract, FileName: "contract.pdf", ContentType: "application/pdf", Size: 8, Checksum: "sum", StorageKey: "1/1/contract"})
+	require.NoError(t, err)
+	_, err = audit.AddAuditEntry(ctx, model.AuditEntry{ActorID: 1, Action: model.AuditUserLocked, SubjectUserID: user.ID, Detail: "user locked"})
+	require.NoError(t, err)
+
+	data, err := personalData.GetPersonalData(ctx, employee.ID, now)
+	require.NoError(t, err)
+	require.Equal(t, "jane@company.com", data.Employee.Email)
+	require.Equal(t, "jane", data.User.UserName)
+	require.True(t, data.User.TwoFactorEnabled)
+	require.Len(t, data.Sessions, 1)
+	require.Len(t, data.APIKeys, 1)
+	require.Len(t, data.LeaveRecords, 1)
+	require.Equal(t, "contract.pdf", data.Documents[0].FileName)
+	require.True(t, data.Employee.HasPhoto)
+	require.Len(t, data.AuditEntries, 1)
+
+	// another tenant neither sees nor erases the employee
+	other := tenant.WithID(context.Background(), 2)
+	_, err = personalData.GetPersonalData(other, employee.ID, now)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = personalData.ErasePersonalData(other, employee.ID, model.AuditEntry{}, now)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	require.ErrorIs(t, (*NewEmployee(s)).UpdateEmployeePhoto(other, employee.ID, ""), ErrRecordNotFound)
+
+	entry, err := personalData.ErasePersonalData(ctx, employee.ID, model.AuditEntry{ActorID: 1, Detail: "on request"}, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, model.AuditPersonalDataErased, entry.Action)
+	require.Equal(t, user.ID, entry.SubjectUserID)
+
+	erased, err := personalData.GetPersonalData(ctx, employee.ID, now)
+	require.NoError(t, err)
+	require.Equal(t, "Erased", erased.Employee.FirstName)
+	require.NotContains(t, erased.Employee.Email, "jane")
+	require.Nil(t, erased.Employee.Dob)
+	require.NotNil(t, erased.Employee.ErasedAt)
+	require.False(t, erased.Employee.HasPhoto)
+	require.Equal(t, "engineer", erased.Employee.Position)
+	require.NotContains(t, erased.User.UserName, "jane")
+	require.False(t, erased.User.TwoFactorEnabled)
+	require.NotNil(t, erased.User.LockedAt)
+	require.NotNil(t, erased.Sessions[0].RevokedAt)
+	require.NotNil(t, erased.APIKeys[0].RevokedAt)
+	// leave and the audit trail are kept, with the erasure in it
+	require.Len(t, erased.LeaveRecords, 1)
+	require.Len(t, erased.AuditEntries, 2)
+	require.Equal(t, model.AuditPersonalDataErased, erased.AuditEntries[1].Action)
+
+	stored, err := (*NewUser(s)).GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, stored.Password)
+	var dob *string
+	require.NoError(t, s.DB.Raw("SELECT dob FROM employees WHERE id = ?", employee.ID).Row().Scan(&dob))
+	require.Nil(t, dob)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/pii"
+	"employee-management-system/pkg/tenant"
+)
+
+func Test_ReencryptEmployees(t *testing.T) {
+	t.Cleanup(func() { pii.Use(nil) })
+	s := newSQLiteStorage(t)
+	employees := *NewEmployee(s)
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	dob := time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC)
+	rawColumns := func(id int) (email, storedDob string) {
+		row := s.DB.Raw("SELECT email, dob FROM employees WHERE id = ?", id).Row()
+		require.NoError(t, row.Scan(&email, &storedDob))
+		return email, storedDob
+	}
+
+	// rows stored before a keyring was configured are plaintext and still read
+	plain, err := employees.AddEmployee(ctx, model.Employee{FirstName: "Jane", Email: "jane@company.com", Dob: dob})
+	require.NoError(t, err)
+	email, _ := rawColumns(plain.ID)
+	require.Equal(t, "jane@company.com", email)
+
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	first, err := pii.Rotate(path, time.Now())
+	require.NoError(t, err)
+	keyring, err := pii.LoadKeyring(path)
+	require.NoError(t, err)
+	pii.Use(keyring)
+
+	encrypted, err := employees.AddEmployee(ctx, model.Employee{FirstName: "John", Email: "john@company.com", Dob: dob})
+	require.NoError(t, err)
+	email, storedDob := rawColumns(encrypted.ID)
+	require.True(t, pii.IsEncrypted(email))
+	require.True(t, pii.IsEncrypted(storedDob))
+	stored, err := employees.GetEmployeeByID(ctx, encrypted.ID)
+	require.NoError(t, err)
+	require.Equal(t, "john@company.com", stored.Email)
+	require.True(t, dob.Equal(stored.Dob))
+
+	count, err := employees.ReencryptEmployees(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	email, _ = rawColumns(plain.ID)
+	keyID, _ := pii.KeyID(email)
+	require.Equal(t, first, keyID)
+
+	// the whole address finds the employee through the blind index, whatever its case
+	search := "JANE@company.com"
+	found, _, err := employees.ListEmployees(ctx, model.EmployeeFilter{Search: &search, SearchEmail: true}, pagination.Page{})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, plain.ID, found[0].ID)
+	require.True(t, dob.Equal(found[0].Dob))
+
+	// after rotating, re-encrypting moves every row to the new key
+	second, err := pii.Rotate(path, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	keyring, err = pii.LoadKeyring(path)
+	require.NoError(t, err)
+	pii.Use(keyring)
+	_, err = employees.ReencryptEmployees(ctx, 10)
+	require.NoError(t, err)
+	for _, id := range []int{plain.ID, encrypted.ID} {
+		email, storedDob = rawColumns(id)
+		emailKey, _ := pii.KeyID(email)
+		dobKey, _ := pii.KeyID(storedDob)
+		require.Equal(t, []string{second, second}, []string{emailKey, dobKey})
+	}
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"employee-management-system/pkg/tenant"
+)
+
+// tenantColumn scopes every model that has it to the tenant of the query context
+const tenantColumn = "tenant_id"
+
+type tenantScope struct{}
+
+var _ gorm.Plugin = tenantScope{}
+
+// Name of the gorm plugin
+func (tenantScope) Name() string {
+	return "tenant"
+}
+
+// Initialize registers the callbacks filtering reads, updates and deletes by tenant and stamping
+// creates with it. Raw SQL bypasses them, storage never uses it on tenant data.
+func (tenantScope) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tenant:create", tenantCreate),
+		cb.Query().Before("gorm:query").Register("tenant:query", tenantWhere),
+		cb.Row().Before("gorm:row").Register("tenant:row", tenantWhere),
+		cb.Update().Before("gorm:update").Register("tenant:update", tenantUpdate),
+		cb.Delete().Before("gorm:delete").Register("tenant:delete", tenantWhere),
+	)
+}
+
+// useTenantScope installs the tenant scope on db once
+func useTenantScope(db *gorm.DB) error {
+	if err := db.Use(tenantScope{}); err != nil && !errors.Is(err, gorm.ErrRegistered) {
+		return err
+	}
+	return nil
+}
+
+func tenantField(db *gorm.DB) *schema.Field {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField(tenantColumn)
+}
+
+// tenantWhere restricts the statement to the rows of the context tenant
+func tenantWhere(db *gorm.DB) {
+	field := tenantField(db)
+	if db.Error != nil || field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	if tenant.IsUnscoped(ctx) {
+		return
+	}
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		_ = db.AddError(ErrTenantRequired)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+	}})
+}
+
+// tenantUpdate scopes the statement and keeps rows from moving to another tenant
+func tenantUpdate(db *gorm.DB) {
+	if field := tenantField(db); field != nil {
+		db.Statement.Omits = append(db.Statement.Omits, field.DBName)
+	}
+	tenantWhere(db)
+}
+
+// tenantCreate sets the context tenant on every created row, refusing rows of another tenant. An
+// unscoped context may only create rows naming their tenant.
+func tenantCreate(db *gorm.DB) {
+	field := tenantField(db)
+	if db.Error != nil || field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	id, scoped := tenant.FromContext(ctx)
+	if !scoped && !tenant.IsUnscoped(ctx) {
+		_ = db.AddError(ErrTenantRequired)
+		return
+	}
+
+	stamp := func(row reflect.Value) {
+		current, zero := field.ValueOf(ctx, row)
+		switch {
+		case zero && !scoped:
+			_ = db.AddError(ErrTenantRequired)
+		case zero:
+			_ = db.AddError(field.Set(ctx, row, id))
+		case scoped && current != id:
+			_ = db.AddError(ErrCrossTenant)
+		}
+	}
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			stamp(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		stamp(rv)
+	}
+}
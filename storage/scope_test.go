@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/database"
+	"employee-management-system/pkg/migrate"
+	"employee-management-system/pkg/tenant"
+)
+
+// newSQLiteStorage returns storage over a migrated SQLite database
+func newSQLiteStorage(t *testing.T) *Storage {
+	t.Helper()
+	ctx := context.Background()
+	cfg := config.Database{Driver: config.DatabaseDriverSQLite, Name: "file:" + filepath.Join(t.TempDir(), "scope.db") + "?_foreign_keys=on"}
+	db, err := database.Open(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := migrate.New(db, cfg.Driver)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(ctx, time.Second))
+
+	gormDB, err := gorm.Open(sqlite.Dialector{Conn: db}, &gorm.Config{})
+	require.NoError(t, err)
+	return NewFromDB(gormDB)
+}
+
+func Test_TenantScope(t *testing.T) {
+	s := newSQLiteStorage(t)
+	tenants := *NewTenant(s)
+	employees := *NewEmployee(s)
+	departments := *NewDepartment(s)
+	users := *NewUser(s)
+
+	other, err := tenants.AddTenant(context.Background(), model.Tenant{Name: "Other"})
+	require.NoError(t, err)
+	_, err = tenants.AddTenant(context.Background(), model.Tenant{Name: "Other"})
+	require.ErrorIs(t, err, ErrDuplicateRecord)
+
+	defaultCtx := tenant.WithID(context.Background(), tenant.DefaultID)
+	otherCtx := tenant.WithID(context.Background(), other.ID)
+
+	// creates are stamped with the context tenant
+	mine, err := employees.AddEmployee(defaultCtx, model.Employee{FirstName: "Jane", Email: "jane@company.com"})
+	require.NoError(t, err)
+	require.Equal(t, tenant.DefaultID, mine.TenantID)
+	theirs, err := employees.AddEmployee(otherCtx, model.Employee{FirstName: "John", Email: "john@other.com"})
+	require.NoError(t, err)
+	require.Equal(t, other.ID, theirs.TenantID)
+
+	// rows of another tenant can neither be created, read, updated nor deleted
+	_, err = employees.AddEmployee(defaultCtx, model.Employee{TenantID: other.ID, FirstName: "Eve"})
+	require.ErrorIs(t, err, ErrRecordCreatingFailed)
+	_, err = employees.GetEmployeeByID(defaultCtx, theirs.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	all, err := employees.GetAllEmployees(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, mine.ID, all[0].ID)
+
+	_, err = employees.UpdateEmployeeByID(defaultCtx, theirs.ID, model.Employee{FirstName: "Mallory"})
+	require.NoError(t, err)
+	require.NoError(t, employees.DeleteEmployeeByID(defaultCtx, theirs.ID))
+	stored, err := employees.GetEmployeeByID(otherCtx, theirs.ID)
+	require.NoError(t, err)
+	require.Equal(t, "John", stored.FirstName)
+
+	department, err := departments.AddDepartment(otherCtx, model.Department{DepartmentName: "Sales"})
+	require.NoError(t, err)
+	_, err = departments.UpdateDepartmentByID(defaultCtx, department.ID, model.Department{DepartmentName: "Mine"})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	require.ErrorIs(t, departments.DeleteDepartmentByID(defaultCtx, department.ID), ErrRecordNotFound)
+
+	// an update cannot move a row to another tenant
+	_, err = employees.UpdateEmployeeByID(otherCtx, theirs.ID, model.Employee{FirstName: "Johnny"})
+	require.NoError(t, err)
+	var moved model.Employee
+	require.NoError(t, s.DB.WithContext(otherCtx).Model(&moved).Where("id = ?", theirs.ID).Updates(map[string]interface{}{"tenant_id": tenant.DefaultID}).Error)
+	stored, err = employees.GetEmployeeByID(otherCtx, theirs.ID)
+	require.NoError(t, err)
+	require.Equal(t, other.ID, stored.TenantID)
+
+	// queries without a tenant fail instead of reading everything
+	_, err = employees.GetAllEmployees(context.Background())
+	require.Error(t, err)
+	_, err = employees.AddEmployee(context.Background(), model.Employee{FirstName: "Nobody"})
+	require.ErrorIs(t, err, ErrRecordCreatingFailed)
+
+	// authentication reads across tenants
+	_, err = users.Register(otherCtx, model.User{UserName: &theirs.Email, Password: model.Password("secret-password").Encrypt()})
+	require.NoError(t, err)
+	user, err := users.Authenticate(tenant.Unscoped(context.Background()), theirs.Email, "secret-password")
+	require.NoError(t, err)
+	require.Equal(t, other.ID, user.TenantID)
+}
+
+func Test_SessionTenantScope(t *testing.T) {
+	s := newSQLiteStorage(t)
+	other, err := (*NewTenant(s)).AddTenant(context.Background(), model.Tenant{Name: "Other"})
+	require.NoError(t, err)
+	defaultCtx := tenant.WithID(context.Background(), tenant.DefaultID)
+	otherCtx := tenant.WithID(context.Background(), other.ID)
+	sessions := *NewSession(s)
+	now := time.Now()
+
+	userName := "john"
+	user, err := (*NewUser(s)).Register(otherCtx, model.User{UserName: &userName, Password: "hash"})
+	require.NoError(t, err)
+	session, err := sessions.AddSession(otherCtx, model.Session{UserID: user.ID, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, other.ID, session.TenantID)
+
+	// sessions of another tenant can neither be read nor revoked
+	_, err = sessions.GetSessionByID(defaultCtx, session.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	require.NoError(t, sessions.RevokeSessionByID(defaultCtx, session.ID, now))
+	count, err := sessions.RevokeAllSessionsByUserID(defaultCtx, user.ID, now)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	stored, err := sessions.GetSessionByID(otherCtx, session.ID)
+	require.NoError(t, err)
+	require.True(t, stored.Active(now))
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// SessionDatabase enlist all possible storage operations for login sessions
+//
+//go:generate mockgen -source session.go -destination ./mock/mock_session.go -package mock SessionDatabase
+type SessionDatabase interface {
+	AddSession(ctx context.Context, session model.Session) (model.Session, error)
+	GetSessionByID(ctx context.Context, id int) (model.Session, error)
+	GetActiveSessionsByUserID(ctx context.Context, userID int, at time.Time) ([]*model.Session, error)
+	RevokeSessionByID(ctx context.Context, id int, at time.Time) error
+	RevokeAllSessionsByUserID(ctx context.Context, userID int, at time.Time) (int64, error)
+	UpdateSessionLastSeen(ctx context.Context, id int, at time.Time) error
+}
+
+// Session object
+type Session struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewSession creates a new reference to the Session storage entity
+func NewSession(s *Storage) *SessionDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "session").Logger()
+	session := &Session{
+		logger:  l,
+		storage: s,
+	}
+	sessionDatabase := SessionDatabase(session)
+	return &sessionDatabase
+}
+
+// AddSession adds a new row into the sessions table referencing users by user_id column
+func (s *Session) AddSession(ctx context.Context, session model.Session) (model.Session, error) {
+	db := s.storage.DB.WithContext(ctx).Create(&session)
+	if db.Error != nil {
+		logging.Method(ctx, s.logger, "AddSession").Err(db.Error).Msgf("Session::AddSession error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		return model.Session{}, ErrRecordCreatingFailed
+	}
+	return session, nil
+}
+
+// GetSessionByID retrieves a single row
+func (s *Session) GetSessionByID(ctx context.Context, id int) (model.Session, error) {
+	var session model.Session
+	db := s.storage.DB.WithContext(ctx).Where("id = ?", id).Find(&session)
+	if db.Error != nil || session.ID == 0 {
+		logging.Method(ctx, s.logger, "GetSessionByID").Err(db.Error).Msgf("Session::GetSessionByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return session, ErrRecordNotFound
+	}
+	return session, nil
+}
+
+// GetActiveSessionsByUserID retrieves sessions of a user that are neither revoked nor expired, most recent first
+func (s *Session) GetActiveSessionsByUserID(ctx context.Context, userID int, at time.Time) ([]*model.Session, error) {
+	var sessions []*model.Session
+	db := s.storage.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, at).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+	if db.Error != nil {
+		logging.Method(ctx, s.logger, "GetActiveSessionsByUserID").Err(db.Error).Msgf("Session::GetActiveSessionsByUserID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return sessions, nil
+}
+
+// RevokeSessionByID marks a single session as revoked
+func (s *Session) RevokeSessionByID(ctx context.Context, id int, at time.Time) error {
+	db := s.storage.DB.WithContext(ctx).Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if db.Error != nil {
+		logging.Method(ctx, s.logger, "RevokeSessionByID").Err(db.Error).Msgf("Session::RevokeSessionByID error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	return nil
+}
+
+// RevokeAllSessionsByUserID marks every session of the user as revoked and returns how many were still active
+func (s *Session) RevokeAllSessionsByUserID(ctx context.Context, userID int, at time.Time) (int64, error) {
+	db := s.storage.DB.WithContext(ctx).Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at)
+	if db.Error != nil {
+		logging.Method(ctx, s.logger, "RevokeAllSessionsByUserID").Err(db.Error).Msgf("Session::RevokeAllSessionsByUserID error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return 0, ErrRecordUpdateFailed
+	}
+	return db.RowsAffected, nil
+}
+
+// UpdateSessionLastSeen records when a token of the session was last presented
+func (s *Session) UpdateSessionLastSeen(ctx context.Context, id int, at time.Time) error {
+	db := s.storage.DB.WithContext(ctx).Model(&model.Session{}).Where("id = ?", id).Update("last_seen_at", at)
+	if db.Error != nil {
+		logging.Method(ctx, s.logger, "UpdateSessionLastSeen").Err(db.Error).Msgf("Session::UpdateSessionLastSeen error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	return nil
+}
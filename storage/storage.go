@@ -2,53 +2,79 @@
 package storage
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
-	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
 
 	"employee-management-system/model/pagination"
-	"employee-management-system/pkg/environment"
+	"employee-management-system/pkg/blob"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/database"
 	"employee-management-system/pkg/gorm_sqlmock"
 	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/pii"
 )
 
-const packageName = "storage"
+const (
+	packageName = "storage"
+	// insertBatchSize rows per INSERT keeps the bound parameters under the SQL Server limit of 2100
+	insertBatchSize = 100
+)
 
 // Storage object
 type Storage struct {
 	Logger zerolog.Logger
-	Env    *environment.Env
+	Config *config.Config
 	DB     *gorm.DB
+	// Blobs keeps the content of employee documents, their metadata is in DB
+	Blobs blob.Store
 }
 
-// New Storage, however should panic if it can't be pinged. System should be able to connect to the database
-func New(z zerolog.Logger, env *environment.Env) *Storage {
+// New Storage, however should panic if the connection settings are invalid. Reachability is left to the readiness check
+func New(z zerolog.Logger, cfg *config.Config) *Storage {
 	l := z.With().Str(helper.LogStrKeyModule, packageName).Logger()
+	dialector, err := database.Dialector(cfg.Database)
+	if err != nil {
+		l.Fatal().Err(err)
+		panic(err)
+	}
 	db, err := gorm.Open(
-		sqlserver.Open(fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable TimeZone=%s",
-			env.Get("PG_ADDRESS"),
-			env.Get("PG_PORT"),
-			env.Get("PG_USER"),
-			env.Get("PG_DATABASE"),
-			env.Get("PG_PASSWORD"),
-			env.Get("TIMEZONE"),
-		)),
-		&gorm.Config{},
+		dialector,
+		// an unreachable database is reported by the readiness check instead of failing startup
+		&gorm.Config{DisableAutomaticPing: true},
 	)
 	if err != nil {
 		l.Fatal().Err(err)
 		panic(err)
 	}
+	if err := useTenantScope(db); err != nil {
+		l.Fatal().Err(err)
+		panic(err)
+	}
+	if cfg.PII.KeyringFile == "" {
+		l.Warn().Msg("no PII keyring configured (PII_KEYRING_FILE), personal data is stored unencrypted")
+	} else {
+		keyring, err := pii.LoadKeyring(cfg.PII.KeyringFile)
+		if err != nil {
+			l.Fatal().Err(err)
+			panic(err)
+		}
+		pii.Use(keyring)
+	}
+	blobs, err := blob.New(cfg.Documents)
+	if err != nil {
+		l.Fatal().Err(err)
+		panic(err)
+	}
 
 	return &Storage{
 		Logger: l,
-		Env:    env,
+		Config: cfg,
 		DB:     db,
+		Blobs:  blobs,
 	}
 }
 
@@ -72,8 +98,10 @@ func GetStorage(t *testing.T) (sqlmock.Sqlmock, *Storage) {
 	return mock, NewFromDB(db)
 }
 
-// NewFromDB created a new storage with just the database reference passed in
+// NewFromDB created a new storage with just the database reference passed in, scoped by tenant as New is
 func NewFromDB(db *gorm.DB) *Storage {
+	// the scope only fails to install when callbacks are misconfigured, which tests would reveal
+	_ = useTenantScope(db)
 	return &Storage{
 		DB: db,
 	}
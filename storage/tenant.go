@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// TenantDatabase enlist all possible storage operations for Tenants
+//
+//go:generate mockgen -source tenant.go -destination ./mock/mock_tenant.go -package mock TenantDatabase
+type TenantDatabase interface {
+	AddTenant(ctx context.Context, tenant model.Tenant) (model.Tenant, error)
+	GetTenantByID(ctx context.Context, id int) (model.Tenant, error)
+	GetTenantByName(ctx context.Context, name string) (model.Tenant, error)
+	GetAllTenants(ctx context.Context) ([]*model.Tenant, error)
+}
+
+// Tenant object
+type Tenant struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewTenant creates a new reference to the Tenant storage entity
+func NewTenant(s *Storage) *TenantDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "tenant").Logger()
+	tenant := &Tenant{
+		logger:  l,
+		storage: s,
+	}
+	tenantDatabase := TenantDatabase(tenant)
+	return &tenantDatabase
+}
+
+// AddTenant adds a new row into the tenants table
+func (t *Tenant) AddTenant(ctx context.Context, tenant model.Tenant) (model.Tenant, error) {
+	db := t.storage.DB.WithContext(ctx).Create(&tenant)
+	if db.Error != nil {
+		logging.Method(ctx, t.logger, "AddTenant").Err(db.Error).Msgf("Tenant::AddTenant error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		if strings.Contains(strings.ToLower(db.Error.Error()), "unique") || strings.Contains(db.Error.Error(), "duplicate key") {
+			return model.Tenant{}, ErrDuplicateRecord
+		}
+		return model.Tenant{}, ErrRecordCreatingFailed
+	}
+	return tenant, nil
+}
+
+// GetTenantByID retrieves a single row
+func (t *Tenant) GetTenantByID(ctx context.Context, id int) (model.Tenant, error) {
+	var tenant model.Tenant
+	db := t.storage.DB.WithContext(ctx).Where("id = ?", id).Find(&tenant)
+	if db.Error != nil || tenant.ID == 0 {
+		logging.Method(ctx, t.logger, "GetTenantByID").Err(db.Error).Msgf("Tenant::GetTenantByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return tenant, ErrRecordNotFound
+	}
+	return tenant, nil
+}
+
+// GetTenantByName retrieves the tenant with the unique name
+func (t *Tenant) GetTenantByName(ctx context.Context, name string) (model.Tenant, error) {
+	var tenant model.Tenant
+	db := t.storage.DB.WithContext(ctx).Where("name = ?", name).Find(&tenant)
+	if db.Error != nil || tenant.ID == 0 {
+		logging.Method(ctx, t.logger, "GetTenantByName").Err(db.Error).Msgf("Tenant::GetTenantByName error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return tenant, ErrRecordNotFound
+	}
+	return tenant, nil
+}
+
+// GetAllTenants retrieves every tenant
+func (t *Tenant) GetAllTenants(ctx context.Context) ([]*model.Tenant, error) {
+	var tenants []*model.Tenant
+	db := t.storage.DB.WithContext(ctx).Order("id").Find(&tenants)
+	if db.Error != nil {
+		logging.Method(ctx, t.logger, "GetAllTenants").Err(db.Error).Msgf("Tenant::GetAllTenants error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return nil, ErrRecordNotFound
+	}
+	return tenants, nil
+}
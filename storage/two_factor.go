@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
+)
+
+// TwoFactorDatabase enlist all possible storage operations for TOTP two-factor authentication
+//
+//go:generate mockgen -source two_factor.go -destination ./mock/mock_two_factor.go -package mock TwoFactorDatabase
+type TwoFactorDatabase interface {
+	StartEnrolment(ctx context.Context, userID int, secret string, recoveryCodeHashes []string) error
+	EnableTOTP(ctx context.Context, userID int, at time.Time) error
+	DisableTOTP(ctx context.Context, userID int) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) error
+}
+
+// TwoFactor object
+type TwoFactor struct {
+	logger  zerolog.Logger
+	storage *Storage
+}
+
+// NewTwoFactor creates a new reference to the TwoFactor storage entity
+func NewTwoFactor(s *Storage) *TwoFactorDatabase {
+	l := s.Logger.With().Str(helper.LogStrKeyLevel, "two_factor").Logger()
+	twoFactor := &TwoFactor{
+		logger:  l,
+		storage: s,
+	}
+	twoFactorDatabase := TwoFactorDatabase(twoFactor)
+	return &twoFactorDatabase
+}
+
+// StartEnrolment stores a pending TOTP secret and replaces the user's recovery codes
+func (t *TwoFactor) StartEnrolment(ctx context.Context, userID int, secret string, recoveryCodeHashes []string) error {
+	err := t.storage.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":     secret,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]model.RecoveryCode, 0, len(recoveryCodeHashes))
+		for _, hash := range recoveryCodeHashes {
+			codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		logging.Method(ctx, t.logger, "StartEnrolment").Err(err).Msgf("TwoFactor::StartEnrolment error: %v, (%v)", ErrRecordUpdateFailed, err)
+		return ErrRecordUpdateFailed
+	}
+	return nil
+}
+
+// EnableTOTP completes enrolment once the first code has been verified
+func (t *TwoFactor) EnableTOTP(ctx context.Context, userID int, at time.Time) error {
+	db := t.storage.DB.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND totp_secret IS NOT NULL", userID).
+		Update("totp_enabled_at", at)
+	if db.Error != nil || db.RowsAffected == 0 {
+		logging.Method(ctx, t.logger, "EnableTOTP").Err(db.Error).Msgf("TwoFactor::EnableTOTP error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	return nil
+}
+
+// DisableTOTP removes the secret and every recovery code of the user
+func (t *TwoFactor) DisableTOTP(ctx context.Context, userID int) error {
+	err := t.storage.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":     nil,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+	})
+	if err != nil {
+		logging.Method(ctx, t.logger, "DisableTOTP").Err(err).Msgf("TwoFactor::DisableTOTP error: %v, (%v)", ErrRecordUpdateFailed, err)
+		return ErrRecordUpdateFailed
+	}
+	return nil
+}
+
+// UseTOTPStep records an accepted time step, failing if it or a later one was already used
+func (t *TwoFactor) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	db := t.storage.DB.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if db.Error != nil {
+		logging.Method(ctx, t.logger, "UseTOTPStep").Err(db.Error).Msgf("TwoFactor::UseTOTPStep error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	if db.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used, failing if there is none matching
+func (t *TwoFactor) UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) error {
+	db := t.storage.DB.WithContext(ctx).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	if db.Error != nil {
+		logging.Method(ctx, t.logger, "UseRecoveryCode").Err(db.Error).Msgf("TwoFactor::UseRecoveryCode error: %v, (%v)", ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	if db.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
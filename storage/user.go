@@ -3,12 +3,13 @@ package storage
 import (
 	"context"
 	"strings"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
 	"employee-management-system/model"
 	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/logging"
 )
 
 // UserDatabase enlist all possible storage operations for Users
@@ -16,8 +17,13 @@ import (
 //go:generate mockgen -source user.go -destination ./mock/mock_user.go -package mock UserDatabase
 type UserDatabase interface {
 	Register(ctx context.Context, user model.User) (model.User, error)
-	GetUserByID(ctx context.Context, id uuid.UUID) (model.User, error)
+	AddUsers(ctx context.Context, users []*model.User) error
+	GetUserByID(ctx context.Context, id int) (model.User, error)
+	GetUserByUserName(ctx context.Context, userName string) (model.User, error)
 	Authenticate(ctx context.Context, email, password string) (*model.User, error)
+	UpdatePassword(ctx context.Context, id int, password model.Password) error
+	UpdateKind(ctx context.Context, id int, kind model.Kind) error
+	UpdateLockedAt(ctx context.Context, id int, lockedAt *time.Time) error
 }
 
 // User object
@@ -42,7 +48,7 @@ func (u *User) Register(ctx context.Context, user model.User) (model.User, error
 
 	db := u.storage.DB.WithContext(ctx).Create(&user)
 	if db.Error != nil {
-		u.logger.Err(db.Error).Msgf("User::Register error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		logging.Method(ctx, u.logger, "Register").Err(db.Error).Msgf("User::Register error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
 		if strings.Contains(db.Error.Error(), "duplicate key value") {
 			return model.User{}, ErrDuplicateRecord
 		}
@@ -51,12 +57,36 @@ func (u *User) Register(ctx context.Context, user model.User) (model.User, error
 	return user, nil
 }
 
+// AddUsers creates the users in batches, setting their IDs. Passwords must already be encrypted.
+func (u *User) AddUsers(ctx context.Context, users []*model.User) error {
+	db := u.storage.DB.WithContext(ctx).CreateInBatches(users, insertBatchSize)
+	if db.Error != nil {
+		logging.Method(ctx, u.logger, "AddUsers").Err(db.Error).Msgf("User::AddUsers error: %v, (%v)", ErrRecordCreatingFailed, db.Error)
+		if strings.Contains(db.Error.Error(), "duplicate key value") {
+			return ErrDuplicateRecord
+		}
+		return ErrRecordCreatingFailed
+	}
+	return nil
+}
+
 // GetUserByID should find a user by it's ID
-func (u *User) GetUserByID(ctx context.Context, id uuid.UUID) (model.User, error) {
+func (u *User) GetUserByID(ctx context.Context, id int) (model.User, error) {
 	var user model.User
-	db := u.storage.DB.WithContext(ctx).Where("id = ?", id.String()).Find(&user)
+	db := u.storage.DB.WithContext(ctx).Where("id = ?", id).Find(&user)
 	if db.Error != nil {
-		u.logger.Err(db.Error).Msgf("User::GetUserByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		logging.Method(ctx, u.logger, "GetUserByID").Err(db.Error).Msgf("User::GetUserByID error: %v, (%v)", ErrRecordNotFound, db.Error)
+		return user, ErrRecordNotFound
+	}
+	return user, nil
+}
+
+// GetUserByUserName should find a user by the unique user name
+func (u *User) GetUserByUserName(ctx context.Context, userName string) (model.User, error) {
+	var user model.User
+	db := u.storage.DB.WithContext(ctx).Where("user_name = ?", userName).Find(&user)
+	if db.Error != nil || user.ID == 0 {
+		logging.Method(ctx, u.logger, "GetUserByUserName").Err(db.Error).Msgf("User::GetUserByUserName error: %v, (%v)", ErrRecordNotFound, db.Error)
 		return user, ErrRecordNotFound
 	}
 	return user, nil
@@ -67,7 +97,7 @@ func (u *User) Authenticate(ctx context.Context, email, password string) (*model
 	var user model.User
 	db := u.storage.DB.WithContext(ctx).Where("user_name = ?", email).Find(&user)
 	if db.Error != nil {
-		u.logger.Err(db.Error).Msgf("User::Authenticate error: %v, (%v)", ErrRecordNotFound, db.Error)
+		logging.Method(ctx, u.logger, "Authenticate").Err(db.Error).Msgf("User::Authenticate error: %v, (%v)", ErrRecordNotFound, db.Error)
 		return nil, ErrRecordNotFound
 	}
 
@@ -78,3 +108,30 @@ func (u *User) Authenticate(ctx context.Context, email, password string) (*model
 
 	return nil, ErrPasswordIncorrect
 }
+
+// UpdatePassword replaces the password of a user, password must already be encrypted
+func (u *User) UpdatePassword(ctx context.Context, id int, password model.Password) error {
+	return u.updateColumn(ctx, "UpdatePassword", id, "password", password)
+}
+
+// UpdateKind changes the role of a user
+func (u *User) UpdateKind(ctx context.Context, id int, kind model.Kind) error {
+	return u.updateColumn(ctx, "UpdateKind", id, "kind", kind)
+}
+
+// UpdateLockedAt locks the user at lockedAt, or unlocks it when nil
+func (u *User) UpdateLockedAt(ctx context.Context, id int, lockedAt *time.Time) error {
+	return u.updateColumn(ctx, "UpdateLockedAt", id, "locked_at", lockedAt)
+}
+
+func (u *User) updateColumn(ctx context.Context, method string, id int, column string, value interface{}) error {
+	db := u.storage.DB.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update(column, value)
+	if db.Error != nil {
+		logging.Method(ctx, u.logger, method).Err(db.Error).Msgf("User::%s error: %v, (%v)", method, ErrRecordUpdateFailed, db.Error)
+		return ErrRecordUpdateFailed
+	}
+	if db.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/model"
+	"employee-management-system/pkg/pii"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/storage"
+)
+
+const (
+	usage = `usage: admin [config flags] <command> <subcommand> [-tenant ID] [flags] [args]
+
+  user create [-role Staff] <username>         password is read from stdin
+  user reset-password <username>                password is read from stdin, logs the user out
+  user set-role <username> <Administrator|Staff>
+  user lock <username>                          blocks sign in and logs the user out
+  user unlock <username>
+  employee import [-format csv|json] <file|->
+  employee export [-format csv|json]
+  employee show <id>
+  personal-data export <employee id>            writes everything held about the employee and its
+                                                user as JSON, recorded in the audit trail
+  personal-data erase -yes <employee id>        anonymizes the employee and its user, keeping leave
+                                                and the audit trail
+  tokens revoke [-api-keys] <username>          logs the user out, -api-keys also revokes its keys
+  tenant create <name>
+  tenant list
+  pii rotate <keyring file>                     adds a new primary key, creating the keyring file
+  pii reencrypt [-batch 500]                    encrypts the personal data of every tenant under the
+                                                primary key of PII_KEYRING_FILE
+
+Commands act on the -tenant company, the default one unless given.
+`
+	minPasswordLength  = 8
+	reencryptBatchSize = 500
+)
+
+var errUsage = errors.New("invalid arguments, run admin without arguments for usage")
+
+// app runs the commands against the controller and user storage
+type app struct {
+	ops       controller.Operations
+	users     storage.UserDatabase
+	tenants   storage.TenantDatabase
+	employees storage.EmployeeDatabase
+	in        io.Reader
+	out       io.Writer
+}
+
+// run dispatches args, the command and subcommand followed by their flags and arguments
+func (a *app) run(ctx context.Context, args []string) error {
+	command, subcommand := args[0], args[1]
+	flags := flag.NewFlagSet(command+" "+subcommand, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	ctx = tenant.WithID(ctx, tenant.DefaultID)
+	flags.Func("tenant", "id of the company acted on", func(value string) error {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid tenant id %q", value)
+		}
+		ctx = tenant.WithID(ctx, id)
+		return nil
+	})
+
+	switch command + " " + subcommand {
+	case "user create":
+		role := flags.String("role", model.Kind(model.KindStaff).String(), "Administrator or Staff")
+		userName, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		return a.createUser(ctx, userName, *role)
+	case "user reset-password":
+		userName, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		password, err := a.readPassword()
+		if err != nil {
+			return err
+		}
+		if err := a.ops.ResetPassword(ctx, userName, password); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "password of %s reset\n", userName)
+		return nil
+	case "user set-role":
+		if err := flags.Parse(args[2:]); err != nil || flags.NArg() != 2 {
+			return errUsage
+		}
+		kind, ok := model.KindFromString(flags.Arg(1))
+		if !ok {
+			return fmt.Errorf("unknown role %q", flags.Arg(1))
+		}
+		if err := a.ops.SetUserKind(ctx, flags.Arg(0), kind); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "%s is now %s\n", flags.Arg(0), kind)
+		return nil
+	case "user lock", "user unlock":
+		userName, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		lock := a.ops.LockUser
+		if subcommand == "unlock" {
+			lock = a.ops.UnlockUser
+		}
+		if err := lock(ctx, userName); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "%s %sed\n", userName, subcommand)
+		return nil
+	case "employee import":
+		format := flags.String("format", formatCSV, "csv or json")
+		path, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		return a.importEmployees(ctx, path, *format)
+	case "employee export":
+		format := flags.String("format", formatCSV, "csv or json")
+		if err := flags.Parse(args[2:]); err != nil || flags.NArg() != 0 {
+			return errUsage
+		}
+		return a.exportEmployees(ctx, *format)
+	case "employee show":
+		id, err := parseEmployeeID(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		employee, err := a.ops.GetEmployeeByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.writeJSON(toRecord(employee))
+	case "personal-data export":
+		id, err := parseEmployeeID(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		data, err := a.ops.ExportPersonalData(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.writeJSON(data)
+	case "personal-data erase":
+		confirmed := flags.Bool("yes", false, "confirm the erasure, it cannot be undone")
+		id, err := parseEmployeeID(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		if !*confirmed {
+			return fmt.Errorf("erasing employee %d cannot be undone, add -yes to confirm", id)
+		}
+		if err := a.ops.ErasePersonalData(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "personal data of employee %d erased\n", id)
+		return nil
+	case "tokens revoke":
+		apiKeys := flags.Bool("api-keys", false, "also revoke the API keys of the user")
+		userName, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		return a.revokeTokens(ctx, userName, *apiKeys)
+	case "tenant create":
+		name, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		created, err := a.tenants.AddTenant(ctx, model.Tenant{Name: name})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "tenant %d %s created\n", created.ID, created.Name)
+		return nil
+	case "pii rotate":
+		path, err := parseOne(flags, args[2:])
+		if err != nil {
+			return err
+		}
+		keyID, err := pii.Rotate(path, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "key %s is now the primary key of %s, run pii reencrypt with it\n", keyID, path)
+		return nil
+	case "pii reencrypt":
+		batch := flags.Int("batch", reencryptBatchSize, "employees written per transaction")
+		if err := flags.Parse(args[2:]); err != nil || flags.NArg() != 0 || *batch <= 0 {
+			return errUsage
+		}
+		keyring := pii.Current()
+		if keyring == nil {
+			return errors.New("no keyring configured, set PII_KEYRING_FILE")
+		}
+		count, err := a.employees.ReencryptEmployees(ctx, *batch)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "%d employees encrypted under key %s\n", count, keyring.Primary())
+		return nil
+	case "tenant list":
+		if err := flags.Parse(args[2:]); err != nil || flags.NArg() != 0 {
+			return errUsage
+		}
+		tenants, err := a.tenants.GetAllTenants(ctx)
+		if err != nil {
+			return err
+		}
+		for _, t := range tenants {
+			fmt.Fprintf(a.out, "%d\t%s\n", t.ID, t.Name)
+		}
+		return nil
+	default:
+		return errUsage
+	}
+}
+
+// parseOne parses flags and returns the single positional argument
+func parseOne(flags *flag.FlagSet, args []string) (string, error) {
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return "", errUsage
+	}
+	return flags.Arg(0), nil
+}
+
+// parseEmployeeID parses flags and returns the single positional argument as an employee id
+func parseEmployeeID(flags *flag.FlagSet, args []string) (int, error) {
+	arg, err := parseOne(flags, args)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid employee id %q", arg)
+	}
+	return id, nil
+}
+
+// writeJSON writes v indented to the output
+func (a *app) writeJSON(v interface{}) error {
+	encoder := json.NewEncoder(a.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// readPassword reads the password from the first line of the input
+func (a *app) readPassword() (model.Password, error) {
+	line, err := bufio.NewReader(a.in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return model.Password(password), nil
+}
+
+func (a *app) createUser(ctx context.Context, userName, role string) error {
+	kind, ok := model.KindFromString(role)
+	if !ok {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	password, err := a.readPassword()
+	if err != nil {
+		return err
+	}
+
+	user, err := a.ops.CreateUser(ctx, userName, password, kind)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "user %d %s (%s) created\n", user.ID, userName, kind)
+	return nil
+}
+
+func (a *app) revokeTokens(ctx context.Context, userName string, apiKeys bool) error {
+	user, err := a.users.GetUserByUserName(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	count, err := a.ops.RevokeAllSessions(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%d sessions of %s revoked\n", count, userName)
+	if !apiKeys {
+		return nil
+	}
+
+	keys, err := a.ops.GetAllAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, key := range keys {
+		if key.UserID != user.ID || !key.Active(now) {
+			continue
+		}
+		if _, err := a.ops.RevokeAPIKey(ctx, key.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "api key %s revoked\n", key.Prefix)
+	}
+	return nil
+}
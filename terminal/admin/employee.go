@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+	dateLayout = "2006-01-02"
+	// exportBatchSize is the number of employees read per page while exporting
+	exportBatchSize = 100
+)
+
+// columns of the CSV format, in order; the JSON format uses the same names
+var columns = []string{"id", "user_id", "first_name", "last_name", "email", "dob", "department_id", "position"}
+
+// record is an employee as imported and exported
+type record struct {
+	ID           int    `json:"id"`
+	UserID       int    `json:"user_id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Email        string `json:"email"`
+	Dob          string `json:"dob"`
+	DepartmentID int    `json:"department_id"`
+	Position     string `json:"position"`
+}
+
+func toRecord(e model.Employee) record {
+	r := record{
+		ID:           e.ID,
+		UserID:       e.UserID,
+		FirstName:    e.FirstName,
+		LastName:     e.LastName,
+		Email:        e.Email,
+		DepartmentID: e.DepartmentID,
+		Position:     e.Position,
+	}
+	if !e.Dob.IsZero() {
+		r.Dob = e.Dob.Format(dateLayout)
+	}
+	return r
+}
+
+// employee returns the record as a new employee, its id is left for the database to assign
+func (r record) employee() (model.Employee, error) {
+	if r.FirstName == "" || r.LastName == "" || r.Email == "" {
+		return model.Employee{}, errors.New("first_name, last_name and email are required")
+	}
+	dob, err := time.Parse(dateLayout, r.Dob)
+	if err != nil {
+		return model.Employee{}, fmt.Errorf("dob %q is not formatted YYYY-MM-DD", r.Dob)
+	}
+	return model.Employee{
+		UserID:       r.UserID,
+		FirstName:    r.FirstName,
+		LastName:     r.LastName,
+		Email:        r.Email,
+		Dob:          dob,
+		DepartmentID: r.DepartmentID,
+		Position:     r.Position,
+	}, nil
+}
+
+// importEmployees adds every employee of the file at path, or stdin for "-". All records are
+// validated before the first one is added, so a malformed file changes nothing.
+func (a *app) importEmployees(ctx context.Context, path, format string) error {
+	in := a.in
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	records, err := readEmployees(in, format)
+	if err != nil {
+		return err
+	}
+	employees := make([]model.Employee, 0, len(records))
+	for i, r := range records {
+		employee, err := r.employee()
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+		employees = append(employees, employee)
+	}
+
+	for i, employee := range employees {
+		added, err := a.ops.AddEmployee(ctx, employee)
+		if err != nil {
+			return fmt.Errorf("record %d (%s): %w, %d imported before it", i+1, employee.Email, err, i)
+		}
+		fmt.Fprintf(a.out, "employee %d %s imported\n", added.ID, added.Email)
+	}
+	fmt.Fprintf(a.out, "%d employees imported\n", len(employees))
+	return nil
+}
+
+// exportEmployees writes every employee, ordered by id, to the output
+func (a *app) exportEmployees(ctx context.Context, format string) error {
+	var records []record
+	for number := pagination.PageDefaultNumber; ; number++ {
+		employees, info, err := a.ops.ListEmployees(ctx, model.EmployeeFilter{}, pagination.NewPage(number, exportBatchSize, "id", false))
+		if err != nil {
+			return err
+		}
+		for _, employee := range employees {
+			records = append(records, toRecord(*employee))
+		}
+		if !info.HasNextPage {
+			break
+		}
+	}
+	return writeEmployees(a.out, format, records)
+}
+
+func readEmployees(in io.Reader, format string) ([]record, error) {
+	switch format {
+	case formatJSON:
+		var records []record
+		if err := json.NewDecoder(in).Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid json: %w", err)
+		}
+		return records, nil
+	case formatCSV:
+		rows, err := csv.NewReader(in).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		// columns are matched by the header so they may come in any order, or be left out
+		index := map[string]int{}
+		for i, name := range rows[0] {
+			index[name] = i
+		}
+		value := func(row []string, name string) string {
+			if i, ok := index[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		number := func(row []string, name string) (int, error) {
+			v := value(row, name)
+			if v == "" {
+				return 0, nil
+			}
+			return strconv.Atoi(v)
+		}
+
+		records := make([]record, 0, len(rows)-1)
+		for i, row := range rows[1:] {
+			r := record{
+				FirstName: value(row, "first_name"),
+				LastName:  value(row, "last_name"),
+				Email:     value(row, "email"),
+				Dob:       value(row, "dob"),
+				Position:  value(row, "position"),
+			}
+			if r.UserID, err = number(row, "user_id"); err != nil {
+				return nil, fmt.Errorf("record %d: invalid user_id: %w", i+1, err)
+			}
+			if r.DepartmentID, err = number(row, "department_id"); err != nil {
+				return nil, fmt.Errorf("record %d: invalid department_id: %w", i+1, err)
+			}
+			records = append(records, r)
+		}
+		return records, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, use csv or json", format)
+	}
+}
+
+func writeEmployees(out io.Writer, format string, records []record) error {
+	switch format {
+	case formatJSON:
+		if records == nil {
+			records = []record{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case formatCSV:
+		w := csv.NewWriter(out)
+		_ = w.Write(columns)
+		for _, r := range records {
+			_ = w.Write([]string{
+				strconv.Itoa(r.ID),
+				strconv.Itoa(r.UserID),
+				r.FirstName,
+				r.LastName,
+				r.Email,
+				r.Dob,
+				strconv.Itoa(r.DepartmentID),
+				r.Position,
+			})
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return fmt.Errorf("unknown format %q, use csv or json", format)
+	}
+}
@@ -0,0 +1,64 @@
+// Package main is the administrative command line: it bootstraps and fixes user accounts, imports
+// and exports employees, revokes tokens, adds tenants, rotates the personal data keys and answers
+// data subject requests straight through the controller and storage, without SQL
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/middleware"
+	"employee-management-system/storage"
+)
+
+// operatorName is the identity the command acts as, the controller logs changes as made by user 0
+const operatorName = "admin-cli"
+
+func main() {
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	adminLogger := logger.With().Str(helper.LogStrKeyModule, "admin").Logger()
+
+	// load and validate the configuration the server runs with
+	cfg, args, err := config.Load("admin", os.Args[1:], ".env")
+	if err != nil {
+		adminLogger.Fatal().Err(err).Msg("admin: configuration")
+	}
+	if err := cfg.Validate(); err != nil {
+		adminLogger.Fatal().Err(err).Msg("admin: invalid configuration")
+	}
+
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	store := storage.New(logger, cfg)
+	defer store.Close()
+	mWare, err := middleware.NewMiddleware(logger, cfg, store)
+	if err != nil {
+		adminLogger.Fatal().Err(err).Msg("admin: middleware")
+	}
+
+	a := &app{
+		ops:       *controller.New(logger, store, mWare),
+		users:     *storage.NewUser(store),
+		tenants:   *storage.NewTenant(store),
+		employees: *storage.NewEmployee(store),
+		in:        os.Stdin,
+		out:       os.Stdout,
+	}
+
+	name := operatorName
+	ctx := middleware.ContextWithUser(context.Background(), &model.User{UserName: &name, Kind: model.KindAdministrator})
+	if err := a.run(ctx, args); err != nil {
+		fmt.Fprintf(os.Stderr, "admin %s %s: %v\n", args[0], args[1], err)
+		os.Exit(1)
+	}
+}
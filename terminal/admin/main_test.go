@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	controller "employee-management-system/controllers"
+	"employee-management-system/model"
+	"employee-management-system/model/pagination"
+	"employee-management-system/pkg/pii"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/storage"
+)
+
+// fakeOperations records the calls the commands make, the embedded interface panics on anything else
+type fakeOperations struct {
+	controller.Operations
+	employees []model.Employee
+	password  model.Password
+	locked    string
+	tenant    int
+	erased    int
+}
+
+func (f *fakeOperations) AddEmployee(_ context.Context, employee model.Employee) (model.Employee, error) {
+	employee.ID = len(f.employees) + 1
+	f.employees = append(f.employees, employee)
+	return employee, nil
+}
+
+func (f *fakeOperations) ListEmployees(_ context.Context, _ model.EmployeeFilter, page pagination.Page) ([]*model.Employee, pagination.PageInfo, error) {
+	number, size := *page.Number, *page.Size
+	var employees []*model.Employee
+	for i := (number - 1) * size; i < len(f.employees) && i < number*size; i++ {
+		employees = append(employees, &f.employees[i])
+	}
+	return employees, pagination.PageInfo{Page: number, Size: size, HasNextPage: number*size < len(f.employees)}, nil
+}
+
+func (f *fakeOperations) CreateUser(_ context.Context, userName string, password model.Password, kind model.Kind) (model.User, error) {
+	f.password = password
+	return model.User{ID: 1, UserName: &userName, Kind: kind}, nil
+}
+
+func (f *fakeOperations) LockUser(ctx context.Context, userName string) error {
+	f.locked = userName
+	f.tenant, _ = tenant.FromContext(ctx)
+	return nil
+}
+
+func (f *fakeOperations) ExportPersonalData(_ context.Context, employeeID int) (model.PersonalData, error) {
+	return model.PersonalData{Employee: model.PersonalEmployee{ID: employeeID, FirstName: "Jane"}}, nil
+}
+
+func (f *fakeOperations) ErasePersonalData(_ context.Context, employeeID int) error {
+	f.erased = employeeID
+	return nil
+}
+
+// fakeEmployees counts the re-encrypted employees
+type fakeEmployees struct {
+	storage.EmployeeDatabase
+	batch int
+}
+
+func (f *fakeEmployees) ReencryptEmployees(_ context.Context, batch int) (int, error) {
+	f.batch = batch
+	return 3, nil
+}
+
+func newApp(ops *fakeOperations, in string) (*app, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &app{ops: ops, in: strings.NewReader(in), out: out}, out
+}
+
+func Test_EmployeeImportExport(t *testing.T) {
+	ops := &fakeOperations{}
+	input := "email,first_name,last_name,dob,department_id\n" +
+		"jane@company.com,Jane,Doe,1990-04-01,3\n" +
+		"john@company.com,John,Roe,1985-12-31,\n"
+	a, out := newApp(ops, input)
+	require.NoError(t, a.run(context.Background(), []string{"employee", "import", "-"}))
+	require.Len(t, ops.employees, 2)
+	require.Equal(t, 3, ops.employees[0].DepartmentID)
+	require.Contains(t, out.String(), "2 employees imported")
+
+	a, out = newApp(ops, "")
+	require.NoError(t, a.run(context.Background(), []string{"employee", "export"}))
+	require.Equal(t, "id,user_id,first_name,last_name,email,dob,department_id,position\n"+
+		"1,0,Jane,Doe,jane@company.com,1990-04-01,3,\n"+
+		"2,0,John,Roe,john@company.com,1985-12-31,0,\n", out.String())
+
+	// a malformed record imports nothing
+	a, _ = newApp(ops, `[{"email":"a@company.com","first_name":"A","last_name":"B","dob":"1990-01-01"},{"email":"c@company.com"}]`)
+	err := a.run(context.Background(), []string{"employee", "import", "-format", "json", "-"})
+	require.ErrorContains(t, err, "record 2")
+	require.Len(t, ops.employees, 2)
+}
+
+func Test_EmployeeExport_Pages(t *testing.T) {
+	ops := &fakeOperations{}
+	for i := 0; i < exportBatchSize+1; i++ {
+		ops.employees = append(ops.employees, model.Employee{ID: i + 1})
+	}
+	a, out := newApp(ops, "")
+	require.NoError(t, a.run(context.Background(), []string{"employee", "export", "-format", "json"}))
+	require.Equal(t, exportBatchSize+1, strings.Count(out.String(), `"id"`))
+}
+
+func Test_UserCommands(t *testing.T) {
+	ops := &fakeOperations{}
+	a, out := newApp(ops, "correct horse\n")
+	require.NoError(t, a.run(context.Background(), []string{"user", "create", "-role", "administrator", "root"}))
+	require.Equal(t, model.Password("correct horse"), ops.password)
+	require.Contains(t, out.String(), "root (Administrator) created")
+
+	a, _ = newApp(ops, "short\n")
+	require.ErrorContains(t, a.run(context.Background(), []string{"user", "create", "jane"}), "at least")
+
+	a, out = newApp(ops, "")
+	require.NoError(t, a.run(context.Background(), []string{"user", "lock", "jane"}))
+	require.Equal(t, "jane", ops.locked)
+	require.Equal(t, "jane locked\n", out.String())
+	require.Equal(t, tenant.DefaultID, ops.tenant)
+
+	// commands act on the company given by -tenant
+	require.NoError(t, a.run(context.Background(), []string{"user", "lock", "-tenant", "2", "john"}))
+	require.Equal(t, 2, ops.tenant)
+	require.ErrorIs(t, a.run(context.Background(), []string{"user", "lock", "-tenant", "0", "john"}), errUsage)
+
+	require.ErrorIs(t, a.run(context.Background(), []string{"user", "delete", "jane"}), errUsage)
+	require.ErrorIs(t, a.run(context.Background(), []string{"user", "lock"}), errUsage)
+}
+
+func Test_PIICommands(t *testing.T) {
+	t.Cleanup(func() { pii.Use(nil) })
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	a, out := newApp(&fakeOperations{}, "")
+	require.NoError(t, a.run(context.Background(), []string{"pii", "rotate", path}))
+	require.Contains(t, out.String(), "is now the primary key of "+path)
+
+	employees := &fakeEmployees{}
+	a.employees = employees
+	require.ErrorContains(t, a.run(context.Background(), []string{"pii", "reencrypt"}), "PII_KEYRING_FILE")
+
+	keyring, err := pii.LoadKeyring(path)
+	require.NoError(t, err)
+	pii.Use(keyring)
+	out.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"pii", "reencrypt", "-batch", "50"}))
+	require.Equal(t, 50, employees.batch)
+	require.Equal(t, "3 employees encrypted under key "+keyring.Primary()+"\n", out.String())
+	require.ErrorIs(t, a.run(context.Background(), []string{"pii", "reencrypt", "-batch", "0"}), errUsage)
+}
+
+func Test_PersonalDataCommands(t *testing.T) {
+	ops := &fakeOperations{}
+	a, out := newApp(ops, "")
+	require.NoError(t, a.run(context.Background(), []string{"personal-data", "export", "4"}))
+	require.Contains(t, out.String(), `"first_name": "Jane"`)
+
+	// erasure cannot be undone and asks for confirmation
+	require.ErrorContains(t, a.run(context.Background(), []string{"personal-data", "erase", "4"}), "-yes")
+	require.Zero(t, ops.erased)
+	out.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"personal-data", "erase", "-yes", "4"}))
+	require.Equal(t, 4, ops.erased)
+	require.Equal(t, "personal data of employee 4 erased\n", out.String())
+	require.ErrorContains(t, a.run(context.Background(), []string{"personal-data", "erase", "-yes", "jane"}), "invalid employee id")
+}
@@ -2,65 +2,149 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
-	_ "github.com/denisenkom/go-mssqldb" // Microsoft SQL Driver
-	"github.com/pressly/goose"
 	"github.com/rs/zerolog"
 
-	"employee-management-system/pkg/environment"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/database"
 	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/migrate"
 )
 
-const dir = "migration"
+// dir holds a directory of migrations per database driver, relative to the repository root
+const dir = "migrations"
 
-var (
-	flags = flag.NewFlagSet("goose", flag.ExitOnError)
-)
+const usage = `usage: goose [config flags] <command> [--dry-run] [args]
+
+commands:
+  up                   apply every pending migration
+  up-by-one            apply the next pending migration
+  up-to VERSION        apply the pending migrations up to VERSION
+  down                 roll back the latest migration
+  down-to VERSION      roll back the migrations after VERSION
+  redo                 roll back and apply again the latest migration
+  reset                roll back every migration
+  status               list the migrations and when they were applied
+  version              print the database version
+  validate             check the embedded migrations of every driver parse and match, without a database
+  create NAME          add an empty SQL migration to ./migrations/<driver> of every driver
+
+--dry-run prints the SQL of up, up-by-one, up-to, down and down-to instead of running it`
+
+// template of a new migration
+const template = `-- +goose Up
+
+-- +goose Down
+
+`
+
+var errUsage = errors.New(usage)
 
 func main() {
 	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
 	gooseLogger := logger.With().Str(helper.LogStrKeyModule, "goose").Logger()
-	_ = flags.Parse(os.Args[1:])
-	args := flags.Args()
 
-	if len(args) < 2 {
-		flags.Usage()
-		return
+	// load and validate the configuration
+	cfg, args, err := config.Load("goose", os.Args[1:], ".env")
+	if err != nil {
+		gooseLogger.Fatal().Err(err).Msg("goose: configuration")
+	}
+
+	if err := run(context.Background(), cfg.Database, args, os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		gooseLogger.Fatal().Err(err).Msgf("goose %v", args)
 	}
+}
 
+func run(ctx context.Context, cfg config.Database, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
 	command := args[0]
 
-	// get the environment
-	env, err := environment.NewLoadFromFile("../../.env")
-	if err != nil {
-		gooseLogger.Panic().Err(err)
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dryRun := flags.Bool("dry-run", false, "print the SQL instead of running it")
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n\n%v", errUsage, err)
 	}
+	arguments := flags.Args()
 
-	db, err := sql.Open("mssql",
-		fmt.Sprintf("sqlserver://%s:%s@%s:%s?%s?sslmode=disable",
-			env.Get("MSSQL_USER"),
-			env.Get("MSSQL_PASSWORD"),
-			env.Get("MSSQL_ADDRESS"),
-			env.Get("MSSQL_PORT"),
-			env.Get("MSSQL_DATABASE")))
+	// commands working on the files only
+	switch command {
+	case "validate":
+		if err := migrate.Validate(); err != nil {
+			return err
+		}
+		for _, driver := range migrate.Drivers {
+			files, err := migrate.Files(driver)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%d %s migrations are valid\n", len(files), driver)
+		}
+		return nil
+	case "create":
+		if len(arguments) != 1 {
+			return errUsage
+		}
+		return create(arguments[0], time.Now(), out)
+	}
 
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	migrator, err := migrate.New(db, cfg.Driver)
 	if err != nil {
-		gooseLogger.Fatal().Err(err).Msgf("goose %v: %v", command, err)
+		return err
 	}
 
-	var arguments []string
-	for _, val := range args[1:] {
-		if len(val) > 0 {
-			arguments = append(arguments, val)
+	if *dryRun {
+		var version int64
+		if command == "up-to" || command == "down-to" {
+			if len(arguments) != 1 {
+				return errUsage
+			}
+			if version, err = strconv.ParseInt(arguments[0], 10, 64); err != nil {
+				return fmt.Errorf("invalid version %q", arguments[0])
+			}
 		}
+		target, err := migrator.Target(ctx, command, version)
+		if err != nil {
+			return err
+		}
+		return migrator.DryRun(ctx, out, target)
 	}
+	return migrator.Run(ctx, command, arguments...)
+}
 
-	gooseLogger.Info().Msgf("running goose %s %v : args=%d", command, arguments, len(arguments))
-	if err := goose.Run(command, db, dir, arguments...); err != nil {
-		gooseLogger.Fatal().Err(err).Msgf("goose %v: %v", command, err)
+// create adds an empty migration with the same version to the directory of every driver, so they stay in step
+func create(name string, now time.Time, out io.Writer) error {
+	file := fmt.Sprintf("%s_%s.sql", now.UTC().Format("20060102150405"), strings.ToLower(name))
+	for _, driver := range migrate.Drivers {
+		target := filepath.Join(dir, driver, file)
+		if err := os.WriteFile(target, []byte(template), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "created", target)
 	}
+	return nil
 }
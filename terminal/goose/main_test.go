@@ -1,10 +1,29 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"employee-management-system/pkg/config"
 )
 
 func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
+
+func Test_Run_Validate(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), config.Database{}, []string{"validate"}, &out))
+	require.Contains(t, out.String(), "migrations are valid")
+}
+
+func Test_Run_Usage(t *testing.T) {
+	require.ErrorIs(t, run(context.Background(), config.Database{}, nil, io.Discard), errUsage)
+	require.ErrorIs(t, run(context.Background(), config.Database{}, []string{"up", "--unknown"}, io.Discard), errUsage)
+	require.ErrorIs(t, run(context.Background(), config.Database{}, []string{"create"}, io.Discard), errUsage)
+}
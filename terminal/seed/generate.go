@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"employee-management-system/model"
+)
+
+// referenceDate anchors every generated date, so a seed gives the same rows on any day
+var referenceDate = time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	departmentNames = []string{
+		"Engineering", "Sales", "Marketing", "Finance", "Human Resources", "Operations", "Customer Support",
+		"Legal", "Product", "Design", "Research", "Procurement", "Facilities", "Security", "Data", "Quality Assurance",
+	}
+	firstNames = []string{
+		"Ada", "Alan", "Amara", "Ana", "Arjun", "Ben", "Chen", "Chloe", "Daniel", "Divya", "Elena", "Emeka", "Fatima",
+		"Felix", "Grace", "Hana", "Hugo", "Ines", "Isaac", "Jade", "James", "Kai", "Kofi", "Lara", "Leo", "Lucia",
+		"Maya", "Mohammed", "Nadia", "Noah", "Olga", "Omar", "Priya", "Quinn", "Rosa", "Sam", "Sofia", "Tariq",
+		"Uma", "Victor", "Wei", "Yara", "Yusuf", "Zoe",
+	}
+	lastNames = []string{
+		"Adeyemi", "Andersen", "Bianchi", "Brown", "Chowdhury", "Costa", "Dubois", "Evans", "Fischer", "Garcia",
+		"Haddad", "Hansen", "Ito", "Jensen", "Kim", "Kowalski", "Lee", "Lopez", "Mensah", "Müller", "Nakamura",
+		"Novak", "Okafor", "O'Brien", "Patel", "Petrov", "Quispe", "Rossi", "Santos", "Schmidt", "Singh", "Smith",
+		"Tanaka", "Torres", "Nguyen", "Walker", "Wang", "Yilmaz", "Zhang",
+	}
+	positions = []string{"Associate", "Specialist", "Analyst", "Engineer", "Coordinator", "Consultant"}
+	seniority = []string{"Junior", "", "Senior", "Principal"}
+)
+
+// level of an employee in the reporting lines, managers always have a lower level than their reports
+type level int
+
+const (
+	levelHead level = iota
+	levelLead
+	levelMember
+	levels
+)
+
+// person is a generated employee with the user account and leave that go with it. Department and
+// manager are indexes into dataset.departments and dataset.people, IDs are only known once stored.
+type person struct {
+	user       model.User
+	employee   model.Employee
+	level      level
+	department int
+	manager    int
+	leave      []model.LeaveRecord
+}
+
+// dataset is everything a seed generates
+type dataset struct {
+	departments []model.Department
+	// people ordered by level, so managers always come before their reports
+	people []person
+}
+
+// generate returns size employees spread over departments with reporting lines and leave, the
+// same seed and size always give the same dataset
+func generate(seed int64, size int, password model.Password) dataset {
+	r := rand.New(rand.NewSource(seed))
+	var d dataset
+
+	departments := size / 25
+	if departments < 3 {
+		departments = 3
+	}
+	if departments > len(departmentNames) {
+		departments = len(departmentNames)
+	}
+	for i := 0; i < departments; i++ {
+		d.departments = append(d.departments, model.Department{DepartmentName: departmentNames[i]})
+	}
+
+	// every department has a head, about one lead per eight members and members reporting to a lead
+	byLevel := make([][]person, levels)
+	leads := make([][]int, departments)
+	for i := 0; i < departments; i++ {
+		byLevel[levelHead] = append(byLevel[levelHead], person{level: levelHead, department: i, manager: -1})
+	}
+	rest := size - departments
+	for i := 0; i < rest; i++ {
+		department := r.Intn(departments)
+		if i%9 == 0 {
+			leads[department] = append(leads[department], len(byLevel[levelLead]))
+			byLevel[levelLead] = append(byLevel[levelLead], person{level: levelLead, department: department, manager: department})
+			continue
+		}
+		byLevel[levelMember] = append(byLevel[levelMember], person{level: levelMember, department: department, manager: -1})
+	}
+
+	// managers are indexes into people, heads first, then leads, then members
+	leadOffset := len(byLevel[levelHead])
+	for i := range byLevel[levelMember] {
+		p := &byLevel[levelMember][i]
+		if candidates := leads[p.department]; len(candidates) > 0 {
+			p.manager = leadOffset + candidates[r.Intn(len(candidates))]
+		} else {
+			p.manager = p.department
+		}
+	}
+
+	for _, group := range byLevel {
+		for _, p := range group {
+			index := len(d.people)
+			fill(r, &p, index, d.departments[p.department].DepartmentName, password)
+			d.people = append(d.people, p)
+		}
+	}
+	return d
+}
+
+// fill generates the identity, user account and leave of p
+func fill(r *rand.Rand, p *person, index int, department string, password model.Password) {
+	first := firstNames[r.Intn(len(firstNames))]
+	last := lastNames[r.Intn(len(lastNames))]
+	// the index keeps emails, and so user names, unique however often a name comes up
+	email := fmt.Sprintf("%s.%s.%d@example.com", emailPart(first), emailPart(last), index+1)
+
+	kind := model.Kind(model.KindStaff)
+	if index == 0 {
+		kind = model.KindAdministrator
+	}
+	p.user = model.User{UserName: &email, Password: password, Kind: kind}
+
+	var position string
+	switch p.level {
+	case levelHead:
+		position = "Head of " + department
+	case levelLead:
+		position = "Team Lead"
+	default:
+		position = strings.TrimSpace(seniority[r.Intn(len(seniority))] + " " + positions[r.Intn(len(positions))])
+	}
+	p.employee = model.Employee{
+		FirstName: first,
+		LastName:  last,
+		Email:     email,
+		// between 20 and 65 years old
+		Dob:      referenceDate.AddDate(-20-r.Intn(45), -r.Intn(12), -r.Intn(28)),
+		Position: position,
+	}
+
+	for i, n := 0, r.Intn(5); i < n; i++ {
+		p.leave = append(p.leave, leaveRecord(r))
+	}
+}
+
+// leaveRecord generates an absence within a year either side of the reference date
+func leaveRecord(r *rand.Rand) model.LeaveRecord {
+	kind, days := model.LeaveKindAnnual, 1+r.Intn(10)
+	switch roll := r.Intn(20); {
+	case roll < 4:
+		kind, days = model.LeaveKindSick, 1+r.Intn(5)
+	case roll == 4:
+		kind, days = model.LeaveKindParental, 14+r.Intn(70)
+	case roll == 5:
+		kind = model.LeaveKindUnpaid
+	}
+
+	starts := referenceDate.AddDate(0, 0, r.Intn(730)-365)
+	status := model.LeaveStatusApproved
+	switch {
+	case starts.After(referenceDate) && r.Intn(2) == 0:
+		status = model.LeaveStatusPending
+	case r.Intn(10) == 0:
+		status = model.LeaveStatusRejected
+	}
+	return model.LeaveRecord{
+		Kind:     kind,
+		Status:   status,
+		StartsOn: starts,
+		EndsOn:   starts.AddDate(0, 0, days-1),
+	}
+}
+
+// emailPart lower cases a name and keeps its ASCII letters only
+func emailPart(name string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		if c >= 'a' && c <= 'z' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
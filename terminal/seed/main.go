@@ -0,0 +1,192 @@
+// Package main is the demo data seeder: it fills an empty database with departments, users,
+// employees, reporting lines and leave generated from a seed, through the storage layer
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/rs/zerolog"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/helper"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/storage"
+)
+
+const (
+	usage = `usage: seed [config flags] <employees> [-seed N] [-batch N] [-password P] [-tenant ID]
+
+Generates departments, users, employees, reporting lines and leave for 10 to 100000 employees. The
+same seed and size give the same data, run it against an empty, migrated database. The rows belong
+to the -tenant company, the default one unless given.`
+	minEmployees = 10
+	maxEmployees = 100000
+)
+
+var errUsage = errors.New(usage)
+
+// options of a seed run
+type options struct {
+	seed      int64
+	employees int
+	batch     int
+	password  string
+	tenant    int
+}
+
+// stores the seeder writes to
+type stores struct {
+	departments storage.DepartmentDatabase
+	users       storage.UserDatabase
+	employees   storage.EmployeeDatabase
+	leave       storage.LeaveDatabase
+}
+
+func main() {
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	seedLogger := logger.With().Str(helper.LogStrKeyModule, "seed").Logger()
+
+	// load and validate the configuration the server runs with
+	cfg, args, err := config.Load("seed", os.Args[1:], ".env")
+	if err != nil {
+		seedLogger.Fatal().Err(err).Msg("seed: configuration")
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		seedLogger.Fatal().Err(err).Msg("seed: invalid database configuration")
+	}
+	opts, err := parseOptions(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	store := storage.New(logger, cfg)
+	defer store.Close()
+	s := stores{
+		departments: *storage.NewDepartment(store),
+		users:       *storage.NewUser(store),
+		employees:   *storage.NewEmployee(store),
+		leave:       *storage.NewLeave(store),
+	}
+	if err := seed(context.Background(), s, opts, os.Stdout); err != nil {
+		seedLogger.Fatal().Err(err).Msg("seed")
+	}
+}
+
+func parseOptions(args []string) (options, error) {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	opts := options{}
+	flags.Int64Var(&opts.seed, "seed", 1, "seed of the pseudo-random data")
+	flags.IntVar(&opts.batch, "batch", 1000, "rows handed to storage at once")
+	flags.StringVar(&opts.password, "password", "demo-password", "password of every generated user")
+	flags.IntVar(&opts.tenant, "tenant", tenant.DefaultID, "id of the company owning the rows")
+	if len(args) == 0 {
+		return options{}, errUsage
+	}
+	employees, err := strconv.Atoi(args[0])
+	if err != nil {
+		return options{}, fmt.Errorf("%w\n\ninvalid number of employees %q", errUsage, args[0])
+	}
+	opts.employees = employees
+	if err := flags.Parse(args[1:]); err != nil {
+		return options{}, fmt.Errorf("%w\n\n%v", errUsage, err)
+	}
+
+	switch {
+	case flags.NArg() > 0:
+		return options{}, errUsage
+	case opts.employees < minEmployees || opts.employees > maxEmployees:
+		return options{}, fmt.Errorf("%w\n\nemployees must be between %d and %d", errUsage, minEmployees, maxEmployees)
+	case opts.batch <= 0:
+		return options{}, fmt.Errorf("%w\n\n-batch must be positive", errUsage)
+	case opts.password == "":
+		return options{}, fmt.Errorf("%w\n\n-password must not be empty", errUsage)
+	case opts.tenant <= 0:
+		return options{}, fmt.Errorf("%w\n\n-tenant must be positive", errUsage)
+	}
+	return opts, nil
+}
+
+// seed generates the dataset of opts and stores it. Employees are stored a level at a time, so the
+// IDs of their managers are known.
+func seed(ctx context.Context, s stores, opts options, out io.Writer) error {
+	ctx = tenant.WithID(ctx, opts.tenant)
+	// hashing once keeps seeding 100k users fast, every user shares the password anyway
+	d := generate(opts.seed, opts.employees, model.Password(opts.password).Encrypt())
+
+	departments := make([]*model.Department, len(d.departments))
+	for i := range d.departments {
+		departments[i] = &d.departments[i]
+	}
+	if err := inBatches(ctx, departments, opts.batch, s.departments.AddDepartments); err != nil {
+		return fmt.Errorf("departments: %w", err)
+	}
+
+	users := make([]*model.User, len(d.people))
+	for i := range d.people {
+		users[i] = &d.people[i].user
+	}
+	if err := inBatches(ctx, users, opts.batch, s.users.AddUsers); err != nil {
+		return fmt.Errorf("users: %w", err)
+	}
+
+	for start := 0; start < len(d.people); {
+		end := start
+		for end < len(d.people) && d.people[end].level == d.people[start].level {
+			end++
+		}
+		employees := make([]*model.Employee, 0, end-start)
+		for i := start; i < end; i++ {
+			p := &d.people[i]
+			p.employee.UserID = p.user.ID
+			p.employee.DepartmentID = d.departments[p.department].ID
+			if p.manager >= 0 {
+				p.employee.ManagerID = d.people[p.manager].employee.ID
+			}
+			employees = append(employees, &p.employee)
+		}
+		if err := inBatches(ctx, employees, opts.batch, s.employees.AddEmployees); err != nil {
+			return fmt.Errorf("employees: %w", err)
+		}
+		start = end
+	}
+
+	var leave []*model.LeaveRecord
+	for i := range d.people {
+		p := &d.people[i]
+		for j := range p.leave {
+			p.leave[j].EmployeeID = p.employee.ID
+			leave = append(leave, &p.leave[j])
+		}
+	}
+	if err := inBatches(ctx, leave, opts.batch, s.leave.AddLeaveRecords); err != nil {
+		return fmt.Errorf("leave records: %w", err)
+	}
+
+	fmt.Fprintf(out, "seeded %d departments, %d employees and users, %d leave records (seed %d)\n",
+		len(departments), len(d.people), len(leave), opts.seed)
+	fmt.Fprintf(out, "sign in as the administrator %s with the password given by -password\n", *d.people[0].user.UserName)
+	return nil
+}
+
+// inBatches hands items to add batch rows at a time
+func inBatches[T any](ctx context.Context, items []T, batch int, add func(context.Context, []T) error) error {
+	for start := 0; start < len(items); start += batch {
+		end := start + batch
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := add(ctx, items[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
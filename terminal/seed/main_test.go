@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"employee-management-system/model"
+	"employee-management-system/pkg/config"
+	"employee-management-system/pkg/database"
+	"employee-management-system/pkg/migrate"
+	"employee-management-system/pkg/tenant"
+	"employee-management-system/storage"
+)
+
+func Test_Generate(t *testing.T) {
+	d := generate(7, 250, "hash")
+	require.Equal(t, d, generate(7, 250, "hash"), "the same seed gives the same data")
+	require.NotEqual(t, d, generate(8, 250, "hash"))
+
+	require.Len(t, d.people, 250)
+	require.Len(t, d.departments, 10)
+	emails := map[string]bool{}
+	for i, p := range d.people {
+		require.False(t, emails[p.employee.Email], "unique email %s", p.employee.Email)
+		emails[p.employee.Email] = true
+
+		if p.level == levelHead {
+			require.Equal(t, -1, p.manager)
+			continue
+		}
+		// managers come first and work in the same department one level up
+		require.Less(t, p.manager, i)
+		manager := d.people[p.manager]
+		require.Equal(t, p.department, manager.department)
+		require.Less(t, manager.level, p.level)
+
+		for _, leave := range p.leave {
+			require.False(t, leave.EndsOn.Before(leave.StartsOn))
+		}
+	}
+	require.Equal(t, model.Kind(model.KindAdministrator), d.people[0].user.Kind)
+}
+
+func Test_ParseOptions(t *testing.T) {
+	opts, err := parseOptions([]string{"10", "-seed", "3"})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), opts.seed)
+	require.Equal(t, 10, opts.employees)
+	require.Equal(t, tenant.DefaultID, opts.tenant)
+
+	for _, args := range [][]string{nil, {"9"}, {"100001"}, {"ten"}, {"10", "-batch", "0"}, {"10", "-tenant", "0"}, {"10", "extra"}, {"10", "-unknown"}} {
+		_, err := parseOptions(args)
+		require.ErrorIs(t, err, errUsage, "%v", args)
+	}
+}
+
+func Test_Seed(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Database{Driver: config.DatabaseDriverSQLite, Name: "file:" + filepath.Join(t.TempDir(), "seed.db") + "?_foreign_keys=on"}
+	db, err := database.Open(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := migrate.New(db, cfg.Driver)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(ctx, time.Second))
+
+	gormDB, err := gorm.Open(sqlite.Dialector{Conn: db}, &gorm.Config{})
+	require.NoError(t, err)
+	store := storage.NewFromDB(gormDB)
+	s := stores{
+		departments: *storage.NewDepartment(store),
+		users:       *storage.NewUser(store),
+		employees:   *storage.NewEmployee(store),
+		leave:       *storage.NewLeave(store),
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, seed(ctx, s, options{seed: 1, employees: 120, batch: 7, password: "demo-password", tenant: tenant.DefaultID}, out))
+	require.Contains(t, out.String(), "120 employees")
+
+	count := func(table string) (n int64) {
+		require.NoError(t, gormDB.Table(table).Count(&n).Error)
+		return n
+	}
+	require.Equal(t, int64(120), count("employees"))
+	require.Equal(t, int64(120), count("users"))
+	require.Equal(t, int64(4), count("departments"))
+	require.Positive(t, count("leave_records"))
+
+	// every reporting line stays within a department and the heads report to nobody
+	var crossing, heads int64
+	require.NoError(t, gormDB.Raw(`SELECT COUNT(*) FROM employees e JOIN employees m ON m.id = e.manager_id
+		WHERE m.departmentID <> e.departmentID`).Scan(&crossing).Error)
+	require.Zero(t, crossing)
+	require.NoError(t, gormDB.Raw("SELECT COUNT(*) FROM employees WHERE manager_id IS NULL").Scan(&heads).Error)
+	require.Equal(t, int64(4), heads)
+
+	// the generated users can sign in
+	admin, err := s.users.Authenticate(tenant.WithID(ctx, tenant.DefaultID), *generate(1, 120, "").people[0].user.UserName, "demo-password")
+	require.NoError(t, err)
+	require.Equal(t, model.Kind(model.KindAdministrator), admin.Kind)
+}